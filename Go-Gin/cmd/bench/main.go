@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"bookmark-shortener/internal/bench"
+
+	"github.com/google/uuid"
+)
+
+const usage = `Usage: go run ./cmd/bench [flags] URL
+
+Seeds users and bookmarks through the API at URL, then sends a mix of
+redirect, list and create requests and reports latency percentiles,
+throughput and error rates:
+
+  go run ./cmd/bench -name gin -duration 30s -json gin.json http://localhost:8080
+
+Flags:
+`
+
+func main() {
+	name := flag.String("name", "", "name of the backend in the report (default: the host)")
+	users := flag.Int("users", 10, "users to seed")
+	bookmarks := flag.Int("bookmarks", 20, "bookmarks to seed per user")
+	mixFlag := flag.String("mix", "redirect=80,list=15,create=5", "relative weights of each request type")
+	concurrency := flag.Int("concurrency", 32, "concurrent workers (maximum requests in flight)")
+	rps := flag.Float64("rps", 0, "target request rate; 0 sends requests back to back")
+	duration := flag.Duration("duration", 30*time.Second, "measured duration")
+	warmup := flag.Duration("warmup", 5*time.Second, "unmeasured warmup before the measured duration")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout per request")
+	jsonPath := flag.String("json", "", "also write the report as JSON to this file")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	baseURL := strings.TrimRight(flag.Arg(0), "/")
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		log.Fatalf("Invalid URL %q", flag.Arg(0))
+	}
+	if *name == "" {
+		*name = u.Host
+	}
+	mix, err := bench.ParseMix(*mixFlag)
+	if err != nil {
+		log.Fatal("Invalid -mix: ", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client := &http.Client{
+		Timeout: *timeout,
+		Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			MaxIdleConns:        *concurrency,
+			MaxIdleConnsPerHost: *concurrency,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// Redirect latency is the latency of the 302 itself
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	runID := uuid.NewString()[:8]
+	log.Printf("Seeding %d users with %d bookmarks each", *users, *bookmarks)
+	fixture, err := bench.Seed(ctx, client, baseURL, runID, *users, *bookmarks, *concurrency)
+	if err != nil {
+		log.Fatal("Failed to seed: ", err)
+	}
+
+	cfg := bench.Config{
+		BaseURL:     baseURL,
+		RunID:       runID,
+		Mix:         mix,
+		Concurrency: *concurrency,
+		RPS:         *rps,
+		Warmup:      *warmup,
+		Duration:    *duration,
+	}
+	log.Printf("Running %s for %s after %s warmup", mix, *duration, *warmup)
+	startedAt := time.Now()
+	result, err := bench.Run(ctx, client, cfg, fixture)
+	if err != nil {
+		log.Printf("Run interrupted: %v", err)
+	}
+
+	report := bench.NewReport(*name, cfg, *users, *bookmarks, startedAt, result)
+	if err := report.WriteMarkdown(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	if *jsonPath != "" {
+		f, err := os.Create(*jsonPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		if err := report.WriteJSON(f); err != nil {
+			log.Fatal("Failed to write JSON report: ", err)
+		}
+	}
+}
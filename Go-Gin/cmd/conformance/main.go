@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"bookmark-shortener/internal/conformance"
+)
+
+const usage = `Usage: go run ./cmd/conformance [flags] TARGET...
+
+Runs the API conformance suite against each TARGET and reports where the
+backends diverge. A TARGET is a base URL, optionally named as NAME=URL:
+
+  go run ./cmd/conformance go=http://localhost:8080 express=http://localhost:3000
+
+Flags:
+`
+
+func main() {
+	suitePath := flag.String("suite", "", "scenario file (default: the built-in suite)")
+	format := flag.String("format", "table", "output format: table or junit")
+	output := flag.String("o", "", "write the report to this file instead of stdout")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout per request")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 || (*format != "table" && *format != "junit") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	targets := make([]conformance.Target, 0, flag.NArg())
+	for _, arg := range flag.Args() {
+		target, err := parseTarget(arg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		targets = append(targets, target)
+	}
+
+	suite, err := loadSuite(*suitePath)
+	if err != nil {
+		log.Fatal("Failed to load suite: ", err)
+	}
+
+	runner := conformance.NewRunner(*timeout)
+	var report conformance.Report
+	for _, target := range targets {
+		report.Add(target, runner.Run(context.Background(), target, suite))
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if *format == "junit" {
+		err = report.WriteJUnit(out)
+	} else {
+		err = report.WriteTable(out)
+	}
+	if err != nil {
+		log.Fatal("Failed to write report: ", err)
+	}
+
+	if report.Failed() {
+		os.Exit(1)
+	}
+}
+
+// parseTarget accepts NAME=URL or a bare URL, which is named after its host.
+func parseTarget(arg string) (conformance.Target, error) {
+	name, rawURL, named := strings.Cut(arg, "=")
+	if !named || strings.Contains(name, "/") {
+		name, rawURL = "", arg
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return conformance.Target{}, fmt.Errorf("invalid target %q, expected NAME=URL or URL", arg)
+	}
+	if name == "" {
+		name = u.Host
+	}
+	return conformance.Target{Name: name, BaseURL: rawURL}, nil
+}
+
+func loadSuite(path string) (*conformance.Suite, error) {
+	if path == "" {
+		return conformance.DefaultSuite()
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return conformance.LoadSuite(f)
+}
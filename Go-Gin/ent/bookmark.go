@@ -27,6 +27,10 @@ type Bookmark struct {
 	ShortCode string `json:"short_code,omitempty"`
 	// VisitCount holds the value of the "visit_count" field.
 	VisitCount int `json:"visit_count,omitempty"`
+	// ExpiresAt holds the value of the "expires_at" field.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// MaxVisits holds the value of the "max_visits" field.
+	MaxVisits *int `json:"max_visits,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// UpdatedAt holds the value of the "updated_at" field.
@@ -42,9 +46,11 @@ type Bookmark struct {
 type BookmarkEdges struct {
 	// Owner holds the value of the owner edge.
 	Owner *User `json:"owner,omitempty"`
+	// Clicks holds the value of the clicks edge.
+	Clicks []*ClickEvent `json:"clicks,omitempty"`
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
-	loadedTypes [1]bool
+	loadedTypes [2]bool
 }
 
 // OwnerOrErr returns the Owner value or an error if the edge
@@ -58,16 +64,25 @@ func (e BookmarkEdges) OwnerOrErr() (*User, error) {
 	return nil, &NotLoadedError{edge: "owner"}
 }
 
+// ClicksOrErr returns the Clicks value or an error if the edge
+// was not loaded in eager-loading.
+func (e BookmarkEdges) ClicksOrErr() ([]*ClickEvent, error) {
+	if e.loadedTypes[1] {
+		return e.Clicks, nil
+	}
+	return nil, &NotLoadedError{edge: "clicks"}
+}
+
 // scanValues returns the types for scanning values from sql.Rows.
 func (*Bookmark) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case bookmark.FieldVisitCount:
+		case bookmark.FieldVisitCount, bookmark.FieldMaxVisits:
 			values[i] = new(sql.NullInt64)
 		case bookmark.FieldTitle, bookmark.FieldURL, bookmark.FieldShortCode:
 			values[i] = new(sql.NullString)
-		case bookmark.FieldCreatedAt, bookmark.FieldUpdatedAt:
+		case bookmark.FieldExpiresAt, bookmark.FieldCreatedAt, bookmark.FieldUpdatedAt:
 			values[i] = new(sql.NullTime)
 		case bookmark.FieldID:
 			values[i] = new(uuid.UUID)
@@ -118,6 +133,20 @@ func (b *Bookmark) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				b.VisitCount = int(value.Int64)
 			}
+		case bookmark.FieldExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expires_at", values[i])
+			} else if value.Valid {
+				b.ExpiresAt = new(time.Time)
+				*b.ExpiresAt = value.Time
+			}
+		case bookmark.FieldMaxVisits:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field max_visits", values[i])
+			} else if value.Valid {
+				b.MaxVisits = new(int)
+				*b.MaxVisits = int(value.Int64)
+			}
 		case bookmark.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field created_at", values[i])
@@ -155,6 +184,11 @@ func (b *Bookmark) QueryOwner() *UserQuery {
 	return NewBookmarkClient(b.config).QueryOwner(b)
 }
 
+// QueryClicks queries the "clicks" edge of the Bookmark entity.
+func (b *Bookmark) QueryClicks() *ClickEventQuery {
+	return NewBookmarkClient(b.config).QueryClicks(b)
+}
+
 // Update returns a builder for updating this Bookmark.
 // Note that you need to call Bookmark.Unwrap() before calling this method if this Bookmark
 // was returned from a transaction, and the transaction was committed or rolled back.
@@ -190,6 +224,16 @@ func (b *Bookmark) String() string {
 	builder.WriteString("visit_count=")
 	builder.WriteString(fmt.Sprintf("%v", b.VisitCount))
 	builder.WriteString(", ")
+	if v := b.ExpiresAt; v != nil {
+		builder.WriteString("expires_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := b.MaxVisits; v != nil {
+		builder.WriteString("max_visits=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
 	builder.WriteString("created_at=")
 	builder.WriteString(b.CreatedAt.Format(time.ANSIC))
 	builder.WriteString(", ")
@@ -23,12 +23,18 @@ const (
 	FieldShortCode = "short_code"
 	// FieldVisitCount holds the string denoting the visit_count field in the database.
 	FieldVisitCount = "visit_count"
+	// FieldExpiresAt holds the string denoting the expires_at field in the database.
+	FieldExpiresAt = "expires_at"
+	// FieldMaxVisits holds the string denoting the max_visits field in the database.
+	FieldMaxVisits = "max_visits"
 	// FieldCreatedAt holds the string denoting the created_at field in the database.
 	FieldCreatedAt = "created_at"
 	// FieldUpdatedAt holds the string denoting the updated_at field in the database.
 	FieldUpdatedAt = "updated_at"
 	// EdgeOwner holds the string denoting the owner edge name in mutations.
 	EdgeOwner = "owner"
+	// EdgeClicks holds the string denoting the clicks edge name in mutations.
+	EdgeClicks = "clicks"
 	// Table holds the table name of the bookmark in the database.
 	Table = "bookmarks"
 	// OwnerTable is the table that holds the owner relation/edge.
@@ -38,6 +44,13 @@ const (
 	OwnerInverseTable = "users"
 	// OwnerColumn is the table column denoting the owner relation/edge.
 	OwnerColumn = "user_bookmarks"
+	// ClicksTable is the table that holds the clicks relation/edge.
+	ClicksTable = "click_events"
+	// ClicksInverseTable is the table name for the ClickEvent entity.
+	// It exists in this package in order to avoid circular dependency with the "clickevent" package.
+	ClicksInverseTable = "click_events"
+	// ClicksColumn is the table column denoting the clicks relation/edge.
+	ClicksColumn = "bookmark_clicks"
 )
 
 // Columns holds all SQL columns for bookmark fields.
@@ -47,6 +60,8 @@ var Columns = []string{
 	FieldURL,
 	FieldShortCode,
 	FieldVisitCount,
+	FieldExpiresAt,
+	FieldMaxVisits,
 	FieldCreatedAt,
 	FieldUpdatedAt,
 }
@@ -75,6 +90,8 @@ func ValidColumn(column string) bool {
 var (
 	// DefaultVisitCount holds the default value on creation for the "visit_count" field.
 	DefaultVisitCount int
+	// MaxVisitsValidator is a validator for the "max_visits" field. It is called by the builders before save.
+	MaxVisitsValidator func(int) error
 	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
 	DefaultCreatedAt func() time.Time
 	// DefaultUpdatedAt holds the default value on creation for the "updated_at" field.
@@ -113,6 +130,16 @@ func ByVisitCount(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldVisitCount, opts...).ToFunc()
 }
 
+// ByExpiresAt orders the results by the expires_at field.
+func ByExpiresAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldExpiresAt, opts...).ToFunc()
+}
+
+// ByMaxVisits orders the results by the max_visits field.
+func ByMaxVisits(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMaxVisits, opts...).ToFunc()
+}
+
 // ByCreatedAt orders the results by the created_at field.
 func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
@@ -129,6 +156,20 @@ func ByOwnerField(field string, opts ...sql.OrderTermOption) OrderOption {
 		sqlgraph.OrderByNeighborTerms(s, newOwnerStep(), sql.OrderByField(field, opts...))
 	}
 }
+
+// ByClicksCount orders the results by clicks count.
+func ByClicksCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newClicksStep(), opts...)
+	}
+}
+
+// ByClicks orders the results by clicks terms.
+func ByClicks(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newClicksStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
 func newOwnerStep() *sqlgraph.Step {
 	return sqlgraph.NewStep(
 		sqlgraph.From(Table, FieldID),
@@ -136,3 +177,10 @@ func newOwnerStep() *sqlgraph.Step {
 		sqlgraph.Edge(sqlgraph.M2O, true, OwnerTable, OwnerColumn),
 	)
 }
+func newClicksStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(ClicksInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, false, ClicksTable, ClicksColumn),
+	)
+}
@@ -76,6 +76,16 @@ func VisitCount(v int) predicate.Bookmark {
 	return predicate.Bookmark(sql.FieldEQ(FieldVisitCount, v))
 }
 
+// ExpiresAt applies equality check predicate on the "expires_at" field. It's identical to ExpiresAtEQ.
+func ExpiresAt(v time.Time) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldEQ(FieldExpiresAt, v))
+}
+
+// MaxVisits applies equality check predicate on the "max_visits" field. It's identical to MaxVisitsEQ.
+func MaxVisits(v int) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldEQ(FieldMaxVisits, v))
+}
+
 // CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
 func CreatedAt(v time.Time) predicate.Bookmark {
 	return predicate.Bookmark(sql.FieldEQ(FieldCreatedAt, v))
@@ -321,6 +331,106 @@ func VisitCountLTE(v int) predicate.Bookmark {
 	return predicate.Bookmark(sql.FieldLTE(FieldVisitCount, v))
 }
 
+// ExpiresAtEQ applies the EQ predicate on the "expires_at" field.
+func ExpiresAtEQ(v time.Time) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldEQ(FieldExpiresAt, v))
+}
+
+// ExpiresAtNEQ applies the NEQ predicate on the "expires_at" field.
+func ExpiresAtNEQ(v time.Time) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldNEQ(FieldExpiresAt, v))
+}
+
+// ExpiresAtIn applies the In predicate on the "expires_at" field.
+func ExpiresAtIn(vs ...time.Time) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldIn(FieldExpiresAt, vs...))
+}
+
+// ExpiresAtNotIn applies the NotIn predicate on the "expires_at" field.
+func ExpiresAtNotIn(vs ...time.Time) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldNotIn(FieldExpiresAt, vs...))
+}
+
+// ExpiresAtGT applies the GT predicate on the "expires_at" field.
+func ExpiresAtGT(v time.Time) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldGT(FieldExpiresAt, v))
+}
+
+// ExpiresAtGTE applies the GTE predicate on the "expires_at" field.
+func ExpiresAtGTE(v time.Time) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldGTE(FieldExpiresAt, v))
+}
+
+// ExpiresAtLT applies the LT predicate on the "expires_at" field.
+func ExpiresAtLT(v time.Time) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldLT(FieldExpiresAt, v))
+}
+
+// ExpiresAtLTE applies the LTE predicate on the "expires_at" field.
+func ExpiresAtLTE(v time.Time) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldLTE(FieldExpiresAt, v))
+}
+
+// ExpiresAtIsNil applies the IsNil predicate on the "expires_at" field.
+func ExpiresAtIsNil() predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldIsNull(FieldExpiresAt))
+}
+
+// ExpiresAtNotNil applies the NotNil predicate on the "expires_at" field.
+func ExpiresAtNotNil() predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldNotNull(FieldExpiresAt))
+}
+
+// MaxVisitsEQ applies the EQ predicate on the "max_visits" field.
+func MaxVisitsEQ(v int) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldEQ(FieldMaxVisits, v))
+}
+
+// MaxVisitsNEQ applies the NEQ predicate on the "max_visits" field.
+func MaxVisitsNEQ(v int) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldNEQ(FieldMaxVisits, v))
+}
+
+// MaxVisitsIn applies the In predicate on the "max_visits" field.
+func MaxVisitsIn(vs ...int) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldIn(FieldMaxVisits, vs...))
+}
+
+// MaxVisitsNotIn applies the NotIn predicate on the "max_visits" field.
+func MaxVisitsNotIn(vs ...int) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldNotIn(FieldMaxVisits, vs...))
+}
+
+// MaxVisitsGT applies the GT predicate on the "max_visits" field.
+func MaxVisitsGT(v int) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldGT(FieldMaxVisits, v))
+}
+
+// MaxVisitsGTE applies the GTE predicate on the "max_visits" field.
+func MaxVisitsGTE(v int) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldGTE(FieldMaxVisits, v))
+}
+
+// MaxVisitsLT applies the LT predicate on the "max_visits" field.
+func MaxVisitsLT(v int) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldLT(FieldMaxVisits, v))
+}
+
+// MaxVisitsLTE applies the LTE predicate on the "max_visits" field.
+func MaxVisitsLTE(v int) predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldLTE(FieldMaxVisits, v))
+}
+
+// MaxVisitsIsNil applies the IsNil predicate on the "max_visits" field.
+func MaxVisitsIsNil() predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldIsNull(FieldMaxVisits))
+}
+
+// MaxVisitsNotNil applies the NotNil predicate on the "max_visits" field.
+func MaxVisitsNotNil() predicate.Bookmark {
+	return predicate.Bookmark(sql.FieldNotNull(FieldMaxVisits))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.Bookmark {
 	return predicate.Bookmark(sql.FieldEQ(FieldCreatedAt, v))
@@ -424,6 +534,29 @@ func HasOwnerWith(preds ...predicate.User) predicate.Bookmark {
 	})
 }
 
+// HasClicks applies the HasEdge predicate on the "clicks" edge.
+func HasClicks() predicate.Bookmark {
+	return predicate.Bookmark(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.O2M, false, ClicksTable, ClicksColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasClicksWith applies the HasEdge predicate on the "clicks" edge with a given conditions (other predicates).
+func HasClicksWith(preds ...predicate.ClickEvent) predicate.Bookmark {
+	return predicate.Bookmark(func(s *sql.Selector) {
+		step := newClicksStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
 // And groups predicates with the AND operator between them.
 func And(predicates ...predicate.Bookmark) predicate.Bookmark {
 	return predicate.Bookmark(sql.AndPredicates(predicates...))
@@ -4,6 +4,7 @@ package ent
 
 import (
 	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/ent/clickevent"
 	"bookmark-shortener/ent/user"
 	"context"
 	"errors"
@@ -54,6 +55,34 @@ func (bc *BookmarkCreate) SetNillableVisitCount(i *int) *BookmarkCreate {
 	return bc
 }
 
+// SetExpiresAt sets the "expires_at" field.
+func (bc *BookmarkCreate) SetExpiresAt(t time.Time) *BookmarkCreate {
+	bc.mutation.SetExpiresAt(t)
+	return bc
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (bc *BookmarkCreate) SetNillableExpiresAt(t *time.Time) *BookmarkCreate {
+	if t != nil {
+		bc.SetExpiresAt(*t)
+	}
+	return bc
+}
+
+// SetMaxVisits sets the "max_visits" field.
+func (bc *BookmarkCreate) SetMaxVisits(i int) *BookmarkCreate {
+	bc.mutation.SetMaxVisits(i)
+	return bc
+}
+
+// SetNillableMaxVisits sets the "max_visits" field if the given value is not nil.
+func (bc *BookmarkCreate) SetNillableMaxVisits(i *int) *BookmarkCreate {
+	if i != nil {
+		bc.SetMaxVisits(*i)
+	}
+	return bc
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (bc *BookmarkCreate) SetCreatedAt(t time.Time) *BookmarkCreate {
 	bc.mutation.SetCreatedAt(t)
@@ -115,6 +144,21 @@ func (bc *BookmarkCreate) SetOwner(u *User) *BookmarkCreate {
 	return bc.SetOwnerID(u.ID)
 }
 
+// AddClickIDs adds the "clicks" edge to the ClickEvent entity by IDs.
+func (bc *BookmarkCreate) AddClickIDs(ids ...uuid.UUID) *BookmarkCreate {
+	bc.mutation.AddClickIDs(ids...)
+	return bc
+}
+
+// AddClicks adds the "clicks" edges to the ClickEvent entity.
+func (bc *BookmarkCreate) AddClicks(c ...*ClickEvent) *BookmarkCreate {
+	ids := make([]uuid.UUID, len(c))
+	for i := range c {
+		ids[i] = c[i].ID
+	}
+	return bc.AddClickIDs(ids...)
+}
+
 // Mutation returns the BookmarkMutation object of the builder.
 func (bc *BookmarkCreate) Mutation() *BookmarkMutation {
 	return bc.mutation
@@ -182,6 +226,11 @@ func (bc *BookmarkCreate) check() error {
 	if _, ok := bc.mutation.VisitCount(); !ok {
 		return &ValidationError{Name: "visit_count", err: errors.New(`ent: missing required field "Bookmark.visit_count"`)}
 	}
+	if v, ok := bc.mutation.MaxVisits(); ok {
+		if err := bookmark.MaxVisitsValidator(v); err != nil {
+			return &ValidationError{Name: "max_visits", err: fmt.Errorf(`ent: validator failed for field "Bookmark.max_visits": %w`, err)}
+		}
+	}
 	if _, ok := bc.mutation.CreatedAt(); !ok {
 		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Bookmark.created_at"`)}
 	}
@@ -239,6 +288,14 @@ func (bc *BookmarkCreate) createSpec() (*Bookmark, *sqlgraph.CreateSpec) {
 		_spec.SetField(bookmark.FieldVisitCount, field.TypeInt, value)
 		_node.VisitCount = value
 	}
+	if value, ok := bc.mutation.ExpiresAt(); ok {
+		_spec.SetField(bookmark.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = &value
+	}
+	if value, ok := bc.mutation.MaxVisits(); ok {
+		_spec.SetField(bookmark.FieldMaxVisits, field.TypeInt, value)
+		_node.MaxVisits = &value
+	}
 	if value, ok := bc.mutation.CreatedAt(); ok {
 		_spec.SetField(bookmark.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
@@ -264,6 +321,22 @@ func (bc *BookmarkCreate) createSpec() (*Bookmark, *sqlgraph.CreateSpec) {
 		_node.user_bookmarks = &nodes[0]
 		_spec.Edges = append(_spec.Edges, edge)
 	}
+	if nodes := bc.mutation.ClicksIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   bookmark.ClicksTable,
+			Columns: []string{bookmark.ClicksColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(clickevent.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
 	return _node, _spec
 }
 
@@ -4,9 +4,11 @@ package ent
 
 import (
 	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/ent/clickevent"
 	"bookmark-shortener/ent/predicate"
 	"bookmark-shortener/ent/user"
 	"context"
+	"database/sql/driver"
 	"fmt"
 	"math"
 
@@ -25,7 +27,9 @@ type BookmarkQuery struct {
 	inters     []Interceptor
 	predicates []predicate.Bookmark
 	withOwner  *UserQuery
+	withClicks *ClickEventQuery
 	withFKs    bool
+	modifiers  []func(*sql.Selector)
 	// intermediate query (i.e. traversal path).
 	sql  *sql.Selector
 	path func(context.Context) (*sql.Selector, error)
@@ -84,6 +88,28 @@ func (bq *BookmarkQuery) QueryOwner() *UserQuery {
 	return query
 }
 
+// QueryClicks chains the current query on the "clicks" edge.
+func (bq *BookmarkQuery) QueryClicks() *ClickEventQuery {
+	query := (&ClickEventClient{config: bq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := bq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := bq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(bookmark.Table, bookmark.FieldID, selector),
+			sqlgraph.To(clickevent.Table, clickevent.FieldID),
+			sqlgraph.Edge(sqlgraph.O2M, false, bookmark.ClicksTable, bookmark.ClicksColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(bq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
 // First returns the first Bookmark entity from the query.
 // Returns a *NotFoundError when no Bookmark was found.
 func (bq *BookmarkQuery) First(ctx context.Context) (*Bookmark, error) {
@@ -277,9 +303,11 @@ func (bq *BookmarkQuery) Clone() *BookmarkQuery {
 		inters:     append([]Interceptor{}, bq.inters...),
 		predicates: append([]predicate.Bookmark{}, bq.predicates...),
 		withOwner:  bq.withOwner.Clone(),
+		withClicks: bq.withClicks.Clone(),
 		// clone intermediate query.
-		sql:  bq.sql.Clone(),
-		path: bq.path,
+		sql:       bq.sql.Clone(),
+		path:      bq.path,
+		modifiers: append([]func(*sql.Selector){}, bq.modifiers...),
 	}
 }
 
@@ -294,6 +322,17 @@ func (bq *BookmarkQuery) WithOwner(opts ...func(*UserQuery)) *BookmarkQuery {
 	return bq
 }
 
+// WithClicks tells the query-builder to eager-load the nodes that are connected to
+// the "clicks" edge. The optional arguments are used to configure the query builder of the edge.
+func (bq *BookmarkQuery) WithClicks(opts ...func(*ClickEventQuery)) *BookmarkQuery {
+	query := (&ClickEventClient{config: bq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	bq.withClicks = query
+	return bq
+}
+
 // GroupBy is used to group vertices by one or more fields/columns.
 // It is often used with aggregate functions, like: count, max, mean, min, sum.
 //
@@ -373,8 +412,9 @@ func (bq *BookmarkQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Boo
 		nodes       = []*Bookmark{}
 		withFKs     = bq.withFKs
 		_spec       = bq.querySpec()
-		loadedTypes = [1]bool{
+		loadedTypes = [2]bool{
 			bq.withOwner != nil,
+			bq.withClicks != nil,
 		}
 	)
 	if bq.withOwner != nil {
@@ -392,6 +432,9 @@ func (bq *BookmarkQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Boo
 		node.Edges.loadedTypes = loadedTypes
 		return node.assignValues(columns, values)
 	}
+	if len(bq.modifiers) > 0 {
+		_spec.Modifiers = bq.modifiers
+	}
 	for i := range hooks {
 		hooks[i](ctx, _spec)
 	}
@@ -407,6 +450,13 @@ func (bq *BookmarkQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Boo
 			return nil, err
 		}
 	}
+	if query := bq.withClicks; query != nil {
+		if err := bq.loadClicks(ctx, query, nodes,
+			func(n *Bookmark) { n.Edges.Clicks = []*ClickEvent{} },
+			func(n *Bookmark, e *ClickEvent) { n.Edges.Clicks = append(n.Edges.Clicks, e) }); err != nil {
+			return nil, err
+		}
+	}
 	return nodes, nil
 }
 
@@ -442,9 +492,43 @@ func (bq *BookmarkQuery) loadOwner(ctx context.Context, query *UserQuery, nodes
 	}
 	return nil
 }
+func (bq *BookmarkQuery) loadClicks(ctx context.Context, query *ClickEventQuery, nodes []*Bookmark, init func(*Bookmark), assign func(*Bookmark, *ClickEvent)) error {
+	fks := make([]driver.Value, 0, len(nodes))
+	nodeids := make(map[uuid.UUID]*Bookmark)
+	for i := range nodes {
+		fks = append(fks, nodes[i].ID)
+		nodeids[nodes[i].ID] = nodes[i]
+		if init != nil {
+			init(nodes[i])
+		}
+	}
+	query.withFKs = true
+	query.Where(predicate.ClickEvent(func(s *sql.Selector) {
+		s.Where(sql.InValues(s.C(bookmark.ClicksColumn), fks...))
+	}))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		fk := n.bookmark_clicks
+		if fk == nil {
+			return fmt.Errorf(`foreign-key "bookmark_clicks" is nil for node %v`, n.ID)
+		}
+		node, ok := nodeids[*fk]
+		if !ok {
+			return fmt.Errorf(`unexpected referenced foreign-key "bookmark_clicks" returned %v for node %v`, *fk, n.ID)
+		}
+		assign(node, n)
+	}
+	return nil
+}
 
 func (bq *BookmarkQuery) sqlCount(ctx context.Context) (int, error) {
 	_spec := bq.querySpec()
+	if len(bq.modifiers) > 0 {
+		_spec.Modifiers = bq.modifiers
+	}
 	_spec.Node.Columns = bq.ctx.Fields
 	if len(bq.ctx.Fields) > 0 {
 		_spec.Unique = bq.ctx.Unique != nil && *bq.ctx.Unique
@@ -507,6 +591,9 @@ func (bq *BookmarkQuery) sqlQuery(ctx context.Context) *sql.Selector {
 	if bq.ctx.Unique != nil && *bq.ctx.Unique {
 		selector.Distinct()
 	}
+	for _, m := range bq.modifiers {
+		m(selector)
+	}
 	for _, p := range bq.predicates {
 		p(selector)
 	}
@@ -524,6 +611,12 @@ func (bq *BookmarkQuery) sqlQuery(ctx context.Context) *sql.Selector {
 	return selector
 }
 
+// Modify adds a query modifier for attaching custom logic to queries.
+func (bq *BookmarkQuery) Modify(modifiers ...func(s *sql.Selector)) *BookmarkSelect {
+	bq.modifiers = append(bq.modifiers, modifiers...)
+	return bq.Select()
+}
+
 // BookmarkGroupBy is the group-by builder for Bookmark entities.
 type BookmarkGroupBy struct {
 	selector
@@ -613,3 +706,9 @@ func (bs *BookmarkSelect) sqlScan(ctx context.Context, root *BookmarkQuery, v an
 	defer rows.Close()
 	return sql.ScanSlice(rows, v)
 }
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (bs *BookmarkSelect) Modify(modifiers ...func(s *sql.Selector)) *BookmarkSelect {
+	bs.modifiers = append(bs.modifiers, modifiers...)
+	return bs
+}
@@ -4,6 +4,7 @@ package ent
 
 import (
 	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/ent/clickevent"
 	"bookmark-shortener/ent/predicate"
 	"bookmark-shortener/ent/user"
 	"context"
@@ -20,8 +21,9 @@ import (
 // BookmarkUpdate is the builder for updating Bookmark entities.
 type BookmarkUpdate struct {
 	config
-	hooks    []Hook
-	mutation *BookmarkMutation
+	hooks     []Hook
+	mutation  *BookmarkMutation
+	modifiers []func(*sql.UpdateBuilder)
 }
 
 // Where appends a list predicates to the BookmarkUpdate builder.
@@ -93,6 +95,53 @@ func (bu *BookmarkUpdate) AddVisitCount(i int) *BookmarkUpdate {
 	return bu
 }
 
+// SetExpiresAt sets the "expires_at" field.
+func (bu *BookmarkUpdate) SetExpiresAt(t time.Time) *BookmarkUpdate {
+	bu.mutation.SetExpiresAt(t)
+	return bu
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (bu *BookmarkUpdate) SetNillableExpiresAt(t *time.Time) *BookmarkUpdate {
+	if t != nil {
+		bu.SetExpiresAt(*t)
+	}
+	return bu
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (bu *BookmarkUpdate) ClearExpiresAt() *BookmarkUpdate {
+	bu.mutation.ClearExpiresAt()
+	return bu
+}
+
+// SetMaxVisits sets the "max_visits" field.
+func (bu *BookmarkUpdate) SetMaxVisits(i int) *BookmarkUpdate {
+	bu.mutation.ResetMaxVisits()
+	bu.mutation.SetMaxVisits(i)
+	return bu
+}
+
+// SetNillableMaxVisits sets the "max_visits" field if the given value is not nil.
+func (bu *BookmarkUpdate) SetNillableMaxVisits(i *int) *BookmarkUpdate {
+	if i != nil {
+		bu.SetMaxVisits(*i)
+	}
+	return bu
+}
+
+// AddMaxVisits adds i to the "max_visits" field.
+func (bu *BookmarkUpdate) AddMaxVisits(i int) *BookmarkUpdate {
+	bu.mutation.AddMaxVisits(i)
+	return bu
+}
+
+// ClearMaxVisits clears the value of the "max_visits" field.
+func (bu *BookmarkUpdate) ClearMaxVisits() *BookmarkUpdate {
+	bu.mutation.ClearMaxVisits()
+	return bu
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (bu *BookmarkUpdate) SetCreatedAt(t time.Time) *BookmarkUpdate {
 	bu.mutation.SetCreatedAt(t)
@@ -132,6 +181,21 @@ func (bu *BookmarkUpdate) SetOwner(u *User) *BookmarkUpdate {
 	return bu.SetOwnerID(u.ID)
 }
 
+// AddClickIDs adds the "clicks" edge to the ClickEvent entity by IDs.
+func (bu *BookmarkUpdate) AddClickIDs(ids ...uuid.UUID) *BookmarkUpdate {
+	bu.mutation.AddClickIDs(ids...)
+	return bu
+}
+
+// AddClicks adds the "clicks" edges to the ClickEvent entity.
+func (bu *BookmarkUpdate) AddClicks(c ...*ClickEvent) *BookmarkUpdate {
+	ids := make([]uuid.UUID, len(c))
+	for i := range c {
+		ids[i] = c[i].ID
+	}
+	return bu.AddClickIDs(ids...)
+}
+
 // Mutation returns the BookmarkMutation object of the builder.
 func (bu *BookmarkUpdate) Mutation() *BookmarkMutation {
 	return bu.mutation
@@ -143,6 +207,27 @@ func (bu *BookmarkUpdate) ClearOwner() *BookmarkUpdate {
 	return bu
 }
 
+// ClearClicks clears all "clicks" edges to the ClickEvent entity.
+func (bu *BookmarkUpdate) ClearClicks() *BookmarkUpdate {
+	bu.mutation.ClearClicks()
+	return bu
+}
+
+// RemoveClickIDs removes the "clicks" edge to ClickEvent entities by IDs.
+func (bu *BookmarkUpdate) RemoveClickIDs(ids ...uuid.UUID) *BookmarkUpdate {
+	bu.mutation.RemoveClickIDs(ids...)
+	return bu
+}
+
+// RemoveClicks removes "clicks" edges to ClickEvent entities.
+func (bu *BookmarkUpdate) RemoveClicks(c ...*ClickEvent) *BookmarkUpdate {
+	ids := make([]uuid.UUID, len(c))
+	for i := range c {
+		ids[i] = c[i].ID
+	}
+	return bu.RemoveClickIDs(ids...)
+}
+
 // Save executes the query and returns the number of nodes affected by the update operation.
 func (bu *BookmarkUpdate) Save(ctx context.Context) (int, error) {
 	bu.defaults()
@@ -179,7 +264,26 @@ func (bu *BookmarkUpdate) defaults() {
 	}
 }
 
+// check runs all checks and user-defined validators on the builder.
+func (bu *BookmarkUpdate) check() error {
+	if v, ok := bu.mutation.MaxVisits(); ok {
+		if err := bookmark.MaxVisitsValidator(v); err != nil {
+			return &ValidationError{Name: "max_visits", err: fmt.Errorf(`ent: validator failed for field "Bookmark.max_visits": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (bu *BookmarkUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *BookmarkUpdate {
+	bu.modifiers = append(bu.modifiers, modifiers...)
+	return bu
+}
+
 func (bu *BookmarkUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := bu.check(); err != nil {
+		return n, err
+	}
 	_spec := sqlgraph.NewUpdateSpec(bookmark.Table, bookmark.Columns, sqlgraph.NewFieldSpec(bookmark.FieldID, field.TypeUUID))
 	if ps := bu.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
@@ -203,6 +307,21 @@ func (bu *BookmarkUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := bu.mutation.AddedVisitCount(); ok {
 		_spec.AddField(bookmark.FieldVisitCount, field.TypeInt, value)
 	}
+	if value, ok := bu.mutation.ExpiresAt(); ok {
+		_spec.SetField(bookmark.FieldExpiresAt, field.TypeTime, value)
+	}
+	if bu.mutation.ExpiresAtCleared() {
+		_spec.ClearField(bookmark.FieldExpiresAt, field.TypeTime)
+	}
+	if value, ok := bu.mutation.MaxVisits(); ok {
+		_spec.SetField(bookmark.FieldMaxVisits, field.TypeInt, value)
+	}
+	if value, ok := bu.mutation.AddedMaxVisits(); ok {
+		_spec.AddField(bookmark.FieldMaxVisits, field.TypeInt, value)
+	}
+	if bu.mutation.MaxVisitsCleared() {
+		_spec.ClearField(bookmark.FieldMaxVisits, field.TypeInt)
+	}
 	if value, ok := bu.mutation.CreatedAt(); ok {
 		_spec.SetField(bookmark.FieldCreatedAt, field.TypeTime, value)
 	}
@@ -238,6 +357,52 @@ func (bu *BookmarkUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	if bu.mutation.ClicksCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   bookmark.ClicksTable,
+			Columns: []string{bookmark.ClicksColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(clickevent.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := bu.mutation.RemovedClicksIDs(); len(nodes) > 0 && !bu.mutation.ClicksCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   bookmark.ClicksTable,
+			Columns: []string{bookmark.ClicksColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(clickevent.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := bu.mutation.ClicksIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   bookmark.ClicksTable,
+			Columns: []string{bookmark.ClicksColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(clickevent.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_spec.AddModifiers(bu.modifiers...)
 	if n, err = sqlgraph.UpdateNodes(ctx, bu.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{bookmark.Label}
@@ -253,9 +418,10 @@ func (bu *BookmarkUpdate) sqlSave(ctx context.Context) (n int, err error) {
 // BookmarkUpdateOne is the builder for updating a single Bookmark entity.
 type BookmarkUpdateOne struct {
 	config
-	fields   []string
-	hooks    []Hook
-	mutation *BookmarkMutation
+	fields    []string
+	hooks     []Hook
+	mutation  *BookmarkMutation
+	modifiers []func(*sql.UpdateBuilder)
 }
 
 // SetTitle sets the "title" field.
@@ -321,6 +487,53 @@ func (buo *BookmarkUpdateOne) AddVisitCount(i int) *BookmarkUpdateOne {
 	return buo
 }
 
+// SetExpiresAt sets the "expires_at" field.
+func (buo *BookmarkUpdateOne) SetExpiresAt(t time.Time) *BookmarkUpdateOne {
+	buo.mutation.SetExpiresAt(t)
+	return buo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (buo *BookmarkUpdateOne) SetNillableExpiresAt(t *time.Time) *BookmarkUpdateOne {
+	if t != nil {
+		buo.SetExpiresAt(*t)
+	}
+	return buo
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (buo *BookmarkUpdateOne) ClearExpiresAt() *BookmarkUpdateOne {
+	buo.mutation.ClearExpiresAt()
+	return buo
+}
+
+// SetMaxVisits sets the "max_visits" field.
+func (buo *BookmarkUpdateOne) SetMaxVisits(i int) *BookmarkUpdateOne {
+	buo.mutation.ResetMaxVisits()
+	buo.mutation.SetMaxVisits(i)
+	return buo
+}
+
+// SetNillableMaxVisits sets the "max_visits" field if the given value is not nil.
+func (buo *BookmarkUpdateOne) SetNillableMaxVisits(i *int) *BookmarkUpdateOne {
+	if i != nil {
+		buo.SetMaxVisits(*i)
+	}
+	return buo
+}
+
+// AddMaxVisits adds i to the "max_visits" field.
+func (buo *BookmarkUpdateOne) AddMaxVisits(i int) *BookmarkUpdateOne {
+	buo.mutation.AddMaxVisits(i)
+	return buo
+}
+
+// ClearMaxVisits clears the value of the "max_visits" field.
+func (buo *BookmarkUpdateOne) ClearMaxVisits() *BookmarkUpdateOne {
+	buo.mutation.ClearMaxVisits()
+	return buo
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (buo *BookmarkUpdateOne) SetCreatedAt(t time.Time) *BookmarkUpdateOne {
 	buo.mutation.SetCreatedAt(t)
@@ -360,6 +573,21 @@ func (buo *BookmarkUpdateOne) SetOwner(u *User) *BookmarkUpdateOne {
 	return buo.SetOwnerID(u.ID)
 }
 
+// AddClickIDs adds the "clicks" edge to the ClickEvent entity by IDs.
+func (buo *BookmarkUpdateOne) AddClickIDs(ids ...uuid.UUID) *BookmarkUpdateOne {
+	buo.mutation.AddClickIDs(ids...)
+	return buo
+}
+
+// AddClicks adds the "clicks" edges to the ClickEvent entity.
+func (buo *BookmarkUpdateOne) AddClicks(c ...*ClickEvent) *BookmarkUpdateOne {
+	ids := make([]uuid.UUID, len(c))
+	for i := range c {
+		ids[i] = c[i].ID
+	}
+	return buo.AddClickIDs(ids...)
+}
+
 // Mutation returns the BookmarkMutation object of the builder.
 func (buo *BookmarkUpdateOne) Mutation() *BookmarkMutation {
 	return buo.mutation
@@ -371,6 +599,27 @@ func (buo *BookmarkUpdateOne) ClearOwner() *BookmarkUpdateOne {
 	return buo
 }
 
+// ClearClicks clears all "clicks" edges to the ClickEvent entity.
+func (buo *BookmarkUpdateOne) ClearClicks() *BookmarkUpdateOne {
+	buo.mutation.ClearClicks()
+	return buo
+}
+
+// RemoveClickIDs removes the "clicks" edge to ClickEvent entities by IDs.
+func (buo *BookmarkUpdateOne) RemoveClickIDs(ids ...uuid.UUID) *BookmarkUpdateOne {
+	buo.mutation.RemoveClickIDs(ids...)
+	return buo
+}
+
+// RemoveClicks removes "clicks" edges to ClickEvent entities.
+func (buo *BookmarkUpdateOne) RemoveClicks(c ...*ClickEvent) *BookmarkUpdateOne {
+	ids := make([]uuid.UUID, len(c))
+	for i := range c {
+		ids[i] = c[i].ID
+	}
+	return buo.RemoveClickIDs(ids...)
+}
+
 // Where appends a list predicates to the BookmarkUpdate builder.
 func (buo *BookmarkUpdateOne) Where(ps ...predicate.Bookmark) *BookmarkUpdateOne {
 	buo.mutation.Where(ps...)
@@ -420,7 +669,26 @@ func (buo *BookmarkUpdateOne) defaults() {
 	}
 }
 
+// check runs all checks and user-defined validators on the builder.
+func (buo *BookmarkUpdateOne) check() error {
+	if v, ok := buo.mutation.MaxVisits(); ok {
+		if err := bookmark.MaxVisitsValidator(v); err != nil {
+			return &ValidationError{Name: "max_visits", err: fmt.Errorf(`ent: validator failed for field "Bookmark.max_visits": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (buo *BookmarkUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *BookmarkUpdateOne {
+	buo.modifiers = append(buo.modifiers, modifiers...)
+	return buo
+}
+
 func (buo *BookmarkUpdateOne) sqlSave(ctx context.Context) (_node *Bookmark, err error) {
+	if err := buo.check(); err != nil {
+		return _node, err
+	}
 	_spec := sqlgraph.NewUpdateSpec(bookmark.Table, bookmark.Columns, sqlgraph.NewFieldSpec(bookmark.FieldID, field.TypeUUID))
 	id, ok := buo.mutation.ID()
 	if !ok {
@@ -461,6 +729,21 @@ func (buo *BookmarkUpdateOne) sqlSave(ctx context.Context) (_node *Bookmark, err
 	if value, ok := buo.mutation.AddedVisitCount(); ok {
 		_spec.AddField(bookmark.FieldVisitCount, field.TypeInt, value)
 	}
+	if value, ok := buo.mutation.ExpiresAt(); ok {
+		_spec.SetField(bookmark.FieldExpiresAt, field.TypeTime, value)
+	}
+	if buo.mutation.ExpiresAtCleared() {
+		_spec.ClearField(bookmark.FieldExpiresAt, field.TypeTime)
+	}
+	if value, ok := buo.mutation.MaxVisits(); ok {
+		_spec.SetField(bookmark.FieldMaxVisits, field.TypeInt, value)
+	}
+	if value, ok := buo.mutation.AddedMaxVisits(); ok {
+		_spec.AddField(bookmark.FieldMaxVisits, field.TypeInt, value)
+	}
+	if buo.mutation.MaxVisitsCleared() {
+		_spec.ClearField(bookmark.FieldMaxVisits, field.TypeInt)
+	}
 	if value, ok := buo.mutation.CreatedAt(); ok {
 		_spec.SetField(bookmark.FieldCreatedAt, field.TypeTime, value)
 	}
@@ -496,6 +779,52 @@ func (buo *BookmarkUpdateOne) sqlSave(ctx context.Context) (_node *Bookmark, err
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	if buo.mutation.ClicksCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   bookmark.ClicksTable,
+			Columns: []string{bookmark.ClicksColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(clickevent.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := buo.mutation.RemovedClicksIDs(); len(nodes) > 0 && !buo.mutation.ClicksCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   bookmark.ClicksTable,
+			Columns: []string{bookmark.ClicksColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(clickevent.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := buo.mutation.ClicksIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   bookmark.ClicksTable,
+			Columns: []string{bookmark.ClicksColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(clickevent.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_spec.AddModifiers(buo.modifiers...)
 	_node = &Bookmark{config: buo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
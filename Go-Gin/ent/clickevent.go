@@ -0,0 +1,190 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/ent/clickevent"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+)
+
+// ClickEvent is the model entity for the ClickEvent schema.
+type ClickEvent struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID uuid.UUID `json:"id,omitempty"`
+	// OccurredAt holds the value of the "occurred_at" field.
+	OccurredAt time.Time `json:"occurred_at,omitempty"`
+	// ReferrerHost holds the value of the "referrer_host" field.
+	ReferrerHost string `json:"referrer_host,omitempty"`
+	// UaFamily holds the value of the "ua_family" field.
+	UaFamily string `json:"ua_family,omitempty"`
+	// VisitorHash holds the value of the "visitor_hash" field.
+	VisitorHash string `json:"visitor_hash,omitempty"`
+	// Country holds the value of the "country" field.
+	Country string `json:"country,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the ClickEventQuery when eager-loading is set.
+	Edges           ClickEventEdges `json:"edges"`
+	bookmark_clicks *uuid.UUID
+	selectValues    sql.SelectValues
+}
+
+// ClickEventEdges holds the relations/edges for other nodes in the graph.
+type ClickEventEdges struct {
+	// Bookmark holds the value of the bookmark edge.
+	Bookmark *Bookmark `json:"bookmark,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// BookmarkOrErr returns the Bookmark value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e ClickEventEdges) BookmarkOrErr() (*Bookmark, error) {
+	if e.Bookmark != nil {
+		return e.Bookmark, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: bookmark.Label}
+	}
+	return nil, &NotLoadedError{edge: "bookmark"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*ClickEvent) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case clickevent.FieldReferrerHost, clickevent.FieldUaFamily, clickevent.FieldVisitorHash, clickevent.FieldCountry:
+			values[i] = new(sql.NullString)
+		case clickevent.FieldOccurredAt:
+			values[i] = new(sql.NullTime)
+		case clickevent.FieldID:
+			values[i] = new(uuid.UUID)
+		case clickevent.ForeignKeys[0]: // bookmark_clicks
+			values[i] = &sql.NullScanner{S: new(uuid.UUID)}
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the ClickEvent fields.
+func (ce *ClickEvent) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case clickevent.FieldID:
+			if value, ok := values[i].(*uuid.UUID); !ok {
+				return fmt.Errorf("unexpected type %T for field id", values[i])
+			} else if value != nil {
+				ce.ID = *value
+			}
+		case clickevent.FieldOccurredAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field occurred_at", values[i])
+			} else if value.Valid {
+				ce.OccurredAt = value.Time
+			}
+		case clickevent.FieldReferrerHost:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field referrer_host", values[i])
+			} else if value.Valid {
+				ce.ReferrerHost = value.String
+			}
+		case clickevent.FieldUaFamily:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field ua_family", values[i])
+			} else if value.Valid {
+				ce.UaFamily = value.String
+			}
+		case clickevent.FieldVisitorHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field visitor_hash", values[i])
+			} else if value.Valid {
+				ce.VisitorHash = value.String
+			}
+		case clickevent.FieldCountry:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field country", values[i])
+			} else if value.Valid {
+				ce.Country = value.String
+			}
+		case clickevent.ForeignKeys[0]:
+			if value, ok := values[i].(*sql.NullScanner); !ok {
+				return fmt.Errorf("unexpected type %T for field bookmark_clicks", values[i])
+			} else if value.Valid {
+				ce.bookmark_clicks = new(uuid.UUID)
+				*ce.bookmark_clicks = *value.S.(*uuid.UUID)
+			}
+		default:
+			ce.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the ClickEvent.
+// This includes values selected through modifiers, order, etc.
+func (ce *ClickEvent) Value(name string) (ent.Value, error) {
+	return ce.selectValues.Get(name)
+}
+
+// QueryBookmark queries the "bookmark" edge of the ClickEvent entity.
+func (ce *ClickEvent) QueryBookmark() *BookmarkQuery {
+	return NewClickEventClient(ce.config).QueryBookmark(ce)
+}
+
+// Update returns a builder for updating this ClickEvent.
+// Note that you need to call ClickEvent.Unwrap() before calling this method if this ClickEvent
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (ce *ClickEvent) Update() *ClickEventUpdateOne {
+	return NewClickEventClient(ce.config).UpdateOne(ce)
+}
+
+// Unwrap unwraps the ClickEvent entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (ce *ClickEvent) Unwrap() *ClickEvent {
+	_tx, ok := ce.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: ClickEvent is not a transactional entity")
+	}
+	ce.config.driver = _tx.drv
+	return ce
+}
+
+// String implements the fmt.Stringer.
+func (ce *ClickEvent) String() string {
+	var builder strings.Builder
+	builder.WriteString("ClickEvent(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", ce.ID))
+	builder.WriteString("occurred_at=")
+	builder.WriteString(ce.OccurredAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("referrer_host=")
+	builder.WriteString(ce.ReferrerHost)
+	builder.WriteString(", ")
+	builder.WriteString("ua_family=")
+	builder.WriteString(ce.UaFamily)
+	builder.WriteString(", ")
+	builder.WriteString("visitor_hash=")
+	builder.WriteString(ce.VisitorHash)
+	builder.WriteString(", ")
+	builder.WriteString("country=")
+	builder.WriteString(ce.Country)
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// ClickEvents is a parsable slice of ClickEvent.
+type ClickEvents []*ClickEvent
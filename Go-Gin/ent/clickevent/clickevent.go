@@ -0,0 +1,130 @@
+// Code generated by ent, DO NOT EDIT.
+
+package clickevent
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/google/uuid"
+)
+
+const (
+	// Label holds the string label denoting the clickevent type in the database.
+	Label = "click_event"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldOccurredAt holds the string denoting the occurred_at field in the database.
+	FieldOccurredAt = "occurred_at"
+	// FieldReferrerHost holds the string denoting the referrer_host field in the database.
+	FieldReferrerHost = "referrer_host"
+	// FieldUaFamily holds the string denoting the ua_family field in the database.
+	FieldUaFamily = "ua_family"
+	// FieldVisitorHash holds the string denoting the visitor_hash field in the database.
+	FieldVisitorHash = "visitor_hash"
+	// FieldCountry holds the string denoting the country field in the database.
+	FieldCountry = "country"
+	// EdgeBookmark holds the string denoting the bookmark edge name in mutations.
+	EdgeBookmark = "bookmark"
+	// Table holds the table name of the clickevent in the database.
+	Table = "click_events"
+	// BookmarkTable is the table that holds the bookmark relation/edge.
+	BookmarkTable = "click_events"
+	// BookmarkInverseTable is the table name for the Bookmark entity.
+	// It exists in this package in order to avoid circular dependency with the "bookmark" package.
+	BookmarkInverseTable = "bookmarks"
+	// BookmarkColumn is the table column denoting the bookmark relation/edge.
+	BookmarkColumn = "bookmark_clicks"
+)
+
+// Columns holds all SQL columns for clickevent fields.
+var Columns = []string{
+	FieldID,
+	FieldOccurredAt,
+	FieldReferrerHost,
+	FieldUaFamily,
+	FieldVisitorHash,
+	FieldCountry,
+}
+
+// ForeignKeys holds the SQL foreign-keys that are owned by the "click_events"
+// table and are not defined as standalone fields in the schema.
+var ForeignKeys = []string{
+	"bookmark_clicks",
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	for i := range ForeignKeys {
+		if column == ForeignKeys[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// DefaultOccurredAt holds the default value on creation for the "occurred_at" field.
+	DefaultOccurredAt func() time.Time
+	// DefaultReferrerHost holds the default value on creation for the "referrer_host" field.
+	DefaultReferrerHost string
+	// DefaultUaFamily holds the default value on creation for the "ua_family" field.
+	DefaultUaFamily string
+	// DefaultCountry holds the default value on creation for the "country" field.
+	DefaultCountry string
+	// DefaultID holds the default value on creation for the "id" field.
+	DefaultID func() uuid.UUID
+)
+
+// OrderOption defines the ordering options for the ClickEvent queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByOccurredAt orders the results by the occurred_at field.
+func ByOccurredAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldOccurredAt, opts...).ToFunc()
+}
+
+// ByReferrerHost orders the results by the referrer_host field.
+func ByReferrerHost(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldReferrerHost, opts...).ToFunc()
+}
+
+// ByUaFamily orders the results by the ua_family field.
+func ByUaFamily(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldUaFamily, opts...).ToFunc()
+}
+
+// ByVisitorHash orders the results by the visitor_hash field.
+func ByVisitorHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldVisitorHash, opts...).ToFunc()
+}
+
+// ByCountry orders the results by the country field.
+func ByCountry(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCountry, opts...).ToFunc()
+}
+
+// ByBookmarkField orders the results by bookmark field.
+func ByBookmarkField(field string, opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newBookmarkStep(), sql.OrderByField(field, opts...))
+	}
+}
+func newBookmarkStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(BookmarkInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.M2O, true, BookmarkTable, BookmarkColumn),
+	)
+}
@@ -0,0 +1,420 @@
+// Code generated by ent, DO NOT EDIT.
+
+package clickevent
+
+import (
+	"bookmark-shortener/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/google/uuid"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id uuid.UUID) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id uuid.UUID) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id uuid.UUID) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...uuid.UUID) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...uuid.UUID) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id uuid.UUID) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id uuid.UUID) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id uuid.UUID) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id uuid.UUID) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldLTE(FieldID, id))
+}
+
+// OccurredAt applies equality check predicate on the "occurred_at" field. It's identical to OccurredAtEQ.
+func OccurredAt(v time.Time) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEQ(FieldOccurredAt, v))
+}
+
+// ReferrerHost applies equality check predicate on the "referrer_host" field. It's identical to ReferrerHostEQ.
+func ReferrerHost(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEQ(FieldReferrerHost, v))
+}
+
+// UaFamily applies equality check predicate on the "ua_family" field. It's identical to UaFamilyEQ.
+func UaFamily(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEQ(FieldUaFamily, v))
+}
+
+// VisitorHash applies equality check predicate on the "visitor_hash" field. It's identical to VisitorHashEQ.
+func VisitorHash(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEQ(FieldVisitorHash, v))
+}
+
+// Country applies equality check predicate on the "country" field. It's identical to CountryEQ.
+func Country(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEQ(FieldCountry, v))
+}
+
+// OccurredAtEQ applies the EQ predicate on the "occurred_at" field.
+func OccurredAtEQ(v time.Time) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEQ(FieldOccurredAt, v))
+}
+
+// OccurredAtNEQ applies the NEQ predicate on the "occurred_at" field.
+func OccurredAtNEQ(v time.Time) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldNEQ(FieldOccurredAt, v))
+}
+
+// OccurredAtIn applies the In predicate on the "occurred_at" field.
+func OccurredAtIn(vs ...time.Time) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldIn(FieldOccurredAt, vs...))
+}
+
+// OccurredAtNotIn applies the NotIn predicate on the "occurred_at" field.
+func OccurredAtNotIn(vs ...time.Time) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldNotIn(FieldOccurredAt, vs...))
+}
+
+// OccurredAtGT applies the GT predicate on the "occurred_at" field.
+func OccurredAtGT(v time.Time) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldGT(FieldOccurredAt, v))
+}
+
+// OccurredAtGTE applies the GTE predicate on the "occurred_at" field.
+func OccurredAtGTE(v time.Time) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldGTE(FieldOccurredAt, v))
+}
+
+// OccurredAtLT applies the LT predicate on the "occurred_at" field.
+func OccurredAtLT(v time.Time) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldLT(FieldOccurredAt, v))
+}
+
+// OccurredAtLTE applies the LTE predicate on the "occurred_at" field.
+func OccurredAtLTE(v time.Time) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldLTE(FieldOccurredAt, v))
+}
+
+// ReferrerHostEQ applies the EQ predicate on the "referrer_host" field.
+func ReferrerHostEQ(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEQ(FieldReferrerHost, v))
+}
+
+// ReferrerHostNEQ applies the NEQ predicate on the "referrer_host" field.
+func ReferrerHostNEQ(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldNEQ(FieldReferrerHost, v))
+}
+
+// ReferrerHostIn applies the In predicate on the "referrer_host" field.
+func ReferrerHostIn(vs ...string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldIn(FieldReferrerHost, vs...))
+}
+
+// ReferrerHostNotIn applies the NotIn predicate on the "referrer_host" field.
+func ReferrerHostNotIn(vs ...string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldNotIn(FieldReferrerHost, vs...))
+}
+
+// ReferrerHostGT applies the GT predicate on the "referrer_host" field.
+func ReferrerHostGT(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldGT(FieldReferrerHost, v))
+}
+
+// ReferrerHostGTE applies the GTE predicate on the "referrer_host" field.
+func ReferrerHostGTE(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldGTE(FieldReferrerHost, v))
+}
+
+// ReferrerHostLT applies the LT predicate on the "referrer_host" field.
+func ReferrerHostLT(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldLT(FieldReferrerHost, v))
+}
+
+// ReferrerHostLTE applies the LTE predicate on the "referrer_host" field.
+func ReferrerHostLTE(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldLTE(FieldReferrerHost, v))
+}
+
+// ReferrerHostContains applies the Contains predicate on the "referrer_host" field.
+func ReferrerHostContains(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldContains(FieldReferrerHost, v))
+}
+
+// ReferrerHostHasPrefix applies the HasPrefix predicate on the "referrer_host" field.
+func ReferrerHostHasPrefix(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldHasPrefix(FieldReferrerHost, v))
+}
+
+// ReferrerHostHasSuffix applies the HasSuffix predicate on the "referrer_host" field.
+func ReferrerHostHasSuffix(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldHasSuffix(FieldReferrerHost, v))
+}
+
+// ReferrerHostEqualFold applies the EqualFold predicate on the "referrer_host" field.
+func ReferrerHostEqualFold(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEqualFold(FieldReferrerHost, v))
+}
+
+// ReferrerHostContainsFold applies the ContainsFold predicate on the "referrer_host" field.
+func ReferrerHostContainsFold(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldContainsFold(FieldReferrerHost, v))
+}
+
+// UaFamilyEQ applies the EQ predicate on the "ua_family" field.
+func UaFamilyEQ(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEQ(FieldUaFamily, v))
+}
+
+// UaFamilyNEQ applies the NEQ predicate on the "ua_family" field.
+func UaFamilyNEQ(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldNEQ(FieldUaFamily, v))
+}
+
+// UaFamilyIn applies the In predicate on the "ua_family" field.
+func UaFamilyIn(vs ...string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldIn(FieldUaFamily, vs...))
+}
+
+// UaFamilyNotIn applies the NotIn predicate on the "ua_family" field.
+func UaFamilyNotIn(vs ...string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldNotIn(FieldUaFamily, vs...))
+}
+
+// UaFamilyGT applies the GT predicate on the "ua_family" field.
+func UaFamilyGT(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldGT(FieldUaFamily, v))
+}
+
+// UaFamilyGTE applies the GTE predicate on the "ua_family" field.
+func UaFamilyGTE(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldGTE(FieldUaFamily, v))
+}
+
+// UaFamilyLT applies the LT predicate on the "ua_family" field.
+func UaFamilyLT(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldLT(FieldUaFamily, v))
+}
+
+// UaFamilyLTE applies the LTE predicate on the "ua_family" field.
+func UaFamilyLTE(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldLTE(FieldUaFamily, v))
+}
+
+// UaFamilyContains applies the Contains predicate on the "ua_family" field.
+func UaFamilyContains(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldContains(FieldUaFamily, v))
+}
+
+// UaFamilyHasPrefix applies the HasPrefix predicate on the "ua_family" field.
+func UaFamilyHasPrefix(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldHasPrefix(FieldUaFamily, v))
+}
+
+// UaFamilyHasSuffix applies the HasSuffix predicate on the "ua_family" field.
+func UaFamilyHasSuffix(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldHasSuffix(FieldUaFamily, v))
+}
+
+// UaFamilyEqualFold applies the EqualFold predicate on the "ua_family" field.
+func UaFamilyEqualFold(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEqualFold(FieldUaFamily, v))
+}
+
+// UaFamilyContainsFold applies the ContainsFold predicate on the "ua_family" field.
+func UaFamilyContainsFold(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldContainsFold(FieldUaFamily, v))
+}
+
+// VisitorHashEQ applies the EQ predicate on the "visitor_hash" field.
+func VisitorHashEQ(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEQ(FieldVisitorHash, v))
+}
+
+// VisitorHashNEQ applies the NEQ predicate on the "visitor_hash" field.
+func VisitorHashNEQ(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldNEQ(FieldVisitorHash, v))
+}
+
+// VisitorHashIn applies the In predicate on the "visitor_hash" field.
+func VisitorHashIn(vs ...string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldIn(FieldVisitorHash, vs...))
+}
+
+// VisitorHashNotIn applies the NotIn predicate on the "visitor_hash" field.
+func VisitorHashNotIn(vs ...string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldNotIn(FieldVisitorHash, vs...))
+}
+
+// VisitorHashGT applies the GT predicate on the "visitor_hash" field.
+func VisitorHashGT(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldGT(FieldVisitorHash, v))
+}
+
+// VisitorHashGTE applies the GTE predicate on the "visitor_hash" field.
+func VisitorHashGTE(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldGTE(FieldVisitorHash, v))
+}
+
+// VisitorHashLT applies the LT predicate on the "visitor_hash" field.
+func VisitorHashLT(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldLT(FieldVisitorHash, v))
+}
+
+// VisitorHashLTE applies the LTE predicate on the "visitor_hash" field.
+func VisitorHashLTE(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldLTE(FieldVisitorHash, v))
+}
+
+// VisitorHashContains applies the Contains predicate on the "visitor_hash" field.
+func VisitorHashContains(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldContains(FieldVisitorHash, v))
+}
+
+// VisitorHashHasPrefix applies the HasPrefix predicate on the "visitor_hash" field.
+func VisitorHashHasPrefix(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldHasPrefix(FieldVisitorHash, v))
+}
+
+// VisitorHashHasSuffix applies the HasSuffix predicate on the "visitor_hash" field.
+func VisitorHashHasSuffix(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldHasSuffix(FieldVisitorHash, v))
+}
+
+// VisitorHashEqualFold applies the EqualFold predicate on the "visitor_hash" field.
+func VisitorHashEqualFold(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEqualFold(FieldVisitorHash, v))
+}
+
+// VisitorHashContainsFold applies the ContainsFold predicate on the "visitor_hash" field.
+func VisitorHashContainsFold(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldContainsFold(FieldVisitorHash, v))
+}
+
+// CountryEQ applies the EQ predicate on the "country" field.
+func CountryEQ(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEQ(FieldCountry, v))
+}
+
+// CountryNEQ applies the NEQ predicate on the "country" field.
+func CountryNEQ(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldNEQ(FieldCountry, v))
+}
+
+// CountryIn applies the In predicate on the "country" field.
+func CountryIn(vs ...string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldIn(FieldCountry, vs...))
+}
+
+// CountryNotIn applies the NotIn predicate on the "country" field.
+func CountryNotIn(vs ...string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldNotIn(FieldCountry, vs...))
+}
+
+// CountryGT applies the GT predicate on the "country" field.
+func CountryGT(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldGT(FieldCountry, v))
+}
+
+// CountryGTE applies the GTE predicate on the "country" field.
+func CountryGTE(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldGTE(FieldCountry, v))
+}
+
+// CountryLT applies the LT predicate on the "country" field.
+func CountryLT(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldLT(FieldCountry, v))
+}
+
+// CountryLTE applies the LTE predicate on the "country" field.
+func CountryLTE(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldLTE(FieldCountry, v))
+}
+
+// CountryContains applies the Contains predicate on the "country" field.
+func CountryContains(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldContains(FieldCountry, v))
+}
+
+// CountryHasPrefix applies the HasPrefix predicate on the "country" field.
+func CountryHasPrefix(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldHasPrefix(FieldCountry, v))
+}
+
+// CountryHasSuffix applies the HasSuffix predicate on the "country" field.
+func CountryHasSuffix(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldHasSuffix(FieldCountry, v))
+}
+
+// CountryEqualFold applies the EqualFold predicate on the "country" field.
+func CountryEqualFold(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldEqualFold(FieldCountry, v))
+}
+
+// CountryContainsFold applies the ContainsFold predicate on the "country" field.
+func CountryContainsFold(v string) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.FieldContainsFold(FieldCountry, v))
+}
+
+// HasBookmark applies the HasEdge predicate on the "bookmark" edge.
+func HasBookmark() predicate.ClickEvent {
+	return predicate.ClickEvent(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, BookmarkTable, BookmarkColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasBookmarkWith applies the HasEdge predicate on the "bookmark" edge with a given conditions (other predicates).
+func HasBookmarkWith(preds ...predicate.Bookmark) predicate.ClickEvent {
+	return predicate.ClickEvent(func(s *sql.Selector) {
+		step := newBookmarkStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.ClickEvent) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.ClickEvent) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.ClickEvent) predicate.ClickEvent {
+	return predicate.ClickEvent(sql.NotPredicates(p))
+}
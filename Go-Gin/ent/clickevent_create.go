@@ -0,0 +1,346 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/ent/clickevent"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/google/uuid"
+)
+
+// ClickEventCreate is the builder for creating a ClickEvent entity.
+type ClickEventCreate struct {
+	config
+	mutation *ClickEventMutation
+	hooks    []Hook
+}
+
+// SetOccurredAt sets the "occurred_at" field.
+func (cec *ClickEventCreate) SetOccurredAt(t time.Time) *ClickEventCreate {
+	cec.mutation.SetOccurredAt(t)
+	return cec
+}
+
+// SetNillableOccurredAt sets the "occurred_at" field if the given value is not nil.
+func (cec *ClickEventCreate) SetNillableOccurredAt(t *time.Time) *ClickEventCreate {
+	if t != nil {
+		cec.SetOccurredAt(*t)
+	}
+	return cec
+}
+
+// SetReferrerHost sets the "referrer_host" field.
+func (cec *ClickEventCreate) SetReferrerHost(s string) *ClickEventCreate {
+	cec.mutation.SetReferrerHost(s)
+	return cec
+}
+
+// SetNillableReferrerHost sets the "referrer_host" field if the given value is not nil.
+func (cec *ClickEventCreate) SetNillableReferrerHost(s *string) *ClickEventCreate {
+	if s != nil {
+		cec.SetReferrerHost(*s)
+	}
+	return cec
+}
+
+// SetUaFamily sets the "ua_family" field.
+func (cec *ClickEventCreate) SetUaFamily(s string) *ClickEventCreate {
+	cec.mutation.SetUaFamily(s)
+	return cec
+}
+
+// SetNillableUaFamily sets the "ua_family" field if the given value is not nil.
+func (cec *ClickEventCreate) SetNillableUaFamily(s *string) *ClickEventCreate {
+	if s != nil {
+		cec.SetUaFamily(*s)
+	}
+	return cec
+}
+
+// SetVisitorHash sets the "visitor_hash" field.
+func (cec *ClickEventCreate) SetVisitorHash(s string) *ClickEventCreate {
+	cec.mutation.SetVisitorHash(s)
+	return cec
+}
+
+// SetCountry sets the "country" field.
+func (cec *ClickEventCreate) SetCountry(s string) *ClickEventCreate {
+	cec.mutation.SetCountry(s)
+	return cec
+}
+
+// SetNillableCountry sets the "country" field if the given value is not nil.
+func (cec *ClickEventCreate) SetNillableCountry(s *string) *ClickEventCreate {
+	if s != nil {
+		cec.SetCountry(*s)
+	}
+	return cec
+}
+
+// SetID sets the "id" field.
+func (cec *ClickEventCreate) SetID(u uuid.UUID) *ClickEventCreate {
+	cec.mutation.SetID(u)
+	return cec
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (cec *ClickEventCreate) SetNillableID(u *uuid.UUID) *ClickEventCreate {
+	if u != nil {
+		cec.SetID(*u)
+	}
+	return cec
+}
+
+// SetBookmarkID sets the "bookmark" edge to the Bookmark entity by ID.
+func (cec *ClickEventCreate) SetBookmarkID(id uuid.UUID) *ClickEventCreate {
+	cec.mutation.SetBookmarkID(id)
+	return cec
+}
+
+// SetBookmark sets the "bookmark" edge to the Bookmark entity.
+func (cec *ClickEventCreate) SetBookmark(b *Bookmark) *ClickEventCreate {
+	return cec.SetBookmarkID(b.ID)
+}
+
+// Mutation returns the ClickEventMutation object of the builder.
+func (cec *ClickEventCreate) Mutation() *ClickEventMutation {
+	return cec.mutation
+}
+
+// Save creates the ClickEvent in the database.
+func (cec *ClickEventCreate) Save(ctx context.Context) (*ClickEvent, error) {
+	cec.defaults()
+	return withHooks(ctx, cec.sqlSave, cec.mutation, cec.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (cec *ClickEventCreate) SaveX(ctx context.Context) *ClickEvent {
+	v, err := cec.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (cec *ClickEventCreate) Exec(ctx context.Context) error {
+	_, err := cec.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (cec *ClickEventCreate) ExecX(ctx context.Context) {
+	if err := cec.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (cec *ClickEventCreate) defaults() {
+	if _, ok := cec.mutation.OccurredAt(); !ok {
+		v := clickevent.DefaultOccurredAt()
+		cec.mutation.SetOccurredAt(v)
+	}
+	if _, ok := cec.mutation.ReferrerHost(); !ok {
+		v := clickevent.DefaultReferrerHost
+		cec.mutation.SetReferrerHost(v)
+	}
+	if _, ok := cec.mutation.UaFamily(); !ok {
+		v := clickevent.DefaultUaFamily
+		cec.mutation.SetUaFamily(v)
+	}
+	if _, ok := cec.mutation.Country(); !ok {
+		v := clickevent.DefaultCountry
+		cec.mutation.SetCountry(v)
+	}
+	if _, ok := cec.mutation.ID(); !ok {
+		v := clickevent.DefaultID()
+		cec.mutation.SetID(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (cec *ClickEventCreate) check() error {
+	if _, ok := cec.mutation.OccurredAt(); !ok {
+		return &ValidationError{Name: "occurred_at", err: errors.New(`ent: missing required field "ClickEvent.occurred_at"`)}
+	}
+	if _, ok := cec.mutation.ReferrerHost(); !ok {
+		return &ValidationError{Name: "referrer_host", err: errors.New(`ent: missing required field "ClickEvent.referrer_host"`)}
+	}
+	if _, ok := cec.mutation.UaFamily(); !ok {
+		return &ValidationError{Name: "ua_family", err: errors.New(`ent: missing required field "ClickEvent.ua_family"`)}
+	}
+	if _, ok := cec.mutation.VisitorHash(); !ok {
+		return &ValidationError{Name: "visitor_hash", err: errors.New(`ent: missing required field "ClickEvent.visitor_hash"`)}
+	}
+	if _, ok := cec.mutation.Country(); !ok {
+		return &ValidationError{Name: "country", err: errors.New(`ent: missing required field "ClickEvent.country"`)}
+	}
+	if len(cec.mutation.BookmarkIDs()) == 0 {
+		return &ValidationError{Name: "bookmark", err: errors.New(`ent: missing required edge "ClickEvent.bookmark"`)}
+	}
+	return nil
+}
+
+func (cec *ClickEventCreate) sqlSave(ctx context.Context) (*ClickEvent, error) {
+	if err := cec.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := cec.createSpec()
+	if err := sqlgraph.CreateNode(ctx, cec.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(*uuid.UUID); ok {
+			_node.ID = *id
+		} else if err := _node.ID.Scan(_spec.ID.Value); err != nil {
+			return nil, err
+		}
+	}
+	cec.mutation.id = &_node.ID
+	cec.mutation.done = true
+	return _node, nil
+}
+
+func (cec *ClickEventCreate) createSpec() (*ClickEvent, *sqlgraph.CreateSpec) {
+	var (
+		_node = &ClickEvent{config: cec.config}
+		_spec = sqlgraph.NewCreateSpec(clickevent.Table, sqlgraph.NewFieldSpec(clickevent.FieldID, field.TypeUUID))
+	)
+	if id, ok := cec.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = &id
+	}
+	if value, ok := cec.mutation.OccurredAt(); ok {
+		_spec.SetField(clickevent.FieldOccurredAt, field.TypeTime, value)
+		_node.OccurredAt = value
+	}
+	if value, ok := cec.mutation.ReferrerHost(); ok {
+		_spec.SetField(clickevent.FieldReferrerHost, field.TypeString, value)
+		_node.ReferrerHost = value
+	}
+	if value, ok := cec.mutation.UaFamily(); ok {
+		_spec.SetField(clickevent.FieldUaFamily, field.TypeString, value)
+		_node.UaFamily = value
+	}
+	if value, ok := cec.mutation.VisitorHash(); ok {
+		_spec.SetField(clickevent.FieldVisitorHash, field.TypeString, value)
+		_node.VisitorHash = value
+	}
+	if value, ok := cec.mutation.Country(); ok {
+		_spec.SetField(clickevent.FieldCountry, field.TypeString, value)
+		_node.Country = value
+	}
+	if nodes := cec.mutation.BookmarkIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   clickevent.BookmarkTable,
+			Columns: []string{clickevent.BookmarkColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(bookmark.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.bookmark_clicks = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// ClickEventCreateBulk is the builder for creating many ClickEvent entities in bulk.
+type ClickEventCreateBulk struct {
+	config
+	err      error
+	builders []*ClickEventCreate
+}
+
+// Save creates the ClickEvent entities in the database.
+func (cecb *ClickEventCreateBulk) Save(ctx context.Context) ([]*ClickEvent, error) {
+	if cecb.err != nil {
+		return nil, cecb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(cecb.builders))
+	nodes := make([]*ClickEvent, len(cecb.builders))
+	mutators := make([]Mutator, len(cecb.builders))
+	for i := range cecb.builders {
+		func(i int, root context.Context) {
+			builder := cecb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ClickEventMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, cecb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, cecb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, cecb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (cecb *ClickEventCreateBulk) SaveX(ctx context.Context) []*ClickEvent {
+	v, err := cecb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (cecb *ClickEventCreateBulk) Exec(ctx context.Context) error {
+	_, err := cecb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (cecb *ClickEventCreateBulk) ExecX(ctx context.Context) {
+	if err := cecb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
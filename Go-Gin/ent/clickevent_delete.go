@@ -0,0 +1,88 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"bookmark-shortener/ent/clickevent"
+	"bookmark-shortener/ent/predicate"
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// ClickEventDelete is the builder for deleting a ClickEvent entity.
+type ClickEventDelete struct {
+	config
+	hooks    []Hook
+	mutation *ClickEventMutation
+}
+
+// Where appends a list predicates to the ClickEventDelete builder.
+func (ced *ClickEventDelete) Where(ps ...predicate.ClickEvent) *ClickEventDelete {
+	ced.mutation.Where(ps...)
+	return ced
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (ced *ClickEventDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, ced.sqlExec, ced.mutation, ced.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ced *ClickEventDelete) ExecX(ctx context.Context) int {
+	n, err := ced.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (ced *ClickEventDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := sqlgraph.NewDeleteSpec(clickevent.Table, sqlgraph.NewFieldSpec(clickevent.FieldID, field.TypeUUID))
+	if ps := ced.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, ced.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	ced.mutation.done = true
+	return affected, err
+}
+
+// ClickEventDeleteOne is the builder for deleting a single ClickEvent entity.
+type ClickEventDeleteOne struct {
+	ced *ClickEventDelete
+}
+
+// Where appends a list predicates to the ClickEventDelete builder.
+func (cedo *ClickEventDeleteOne) Where(ps ...predicate.ClickEvent) *ClickEventDeleteOne {
+	cedo.ced.mutation.Where(ps...)
+	return cedo
+}
+
+// Exec executes the deletion query.
+func (cedo *ClickEventDeleteOne) Exec(ctx context.Context) error {
+	n, err := cedo.ced.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{clickevent.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (cedo *ClickEventDeleteOne) ExecX(ctx context.Context) {
+	if err := cedo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
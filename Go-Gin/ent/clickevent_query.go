@@ -0,0 +1,638 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/ent/clickevent"
+	"bookmark-shortener/ent/predicate"
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/google/uuid"
+)
+
+// ClickEventQuery is the builder for querying ClickEvent entities.
+type ClickEventQuery struct {
+	config
+	ctx          *QueryContext
+	order        []clickevent.OrderOption
+	inters       []Interceptor
+	predicates   []predicate.ClickEvent
+	withBookmark *BookmarkQuery
+	withFKs      bool
+	modifiers    []func(*sql.Selector)
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the ClickEventQuery builder.
+func (ceq *ClickEventQuery) Where(ps ...predicate.ClickEvent) *ClickEventQuery {
+	ceq.predicates = append(ceq.predicates, ps...)
+	return ceq
+}
+
+// Limit the number of records to be returned by this query.
+func (ceq *ClickEventQuery) Limit(limit int) *ClickEventQuery {
+	ceq.ctx.Limit = &limit
+	return ceq
+}
+
+// Offset to start from.
+func (ceq *ClickEventQuery) Offset(offset int) *ClickEventQuery {
+	ceq.ctx.Offset = &offset
+	return ceq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (ceq *ClickEventQuery) Unique(unique bool) *ClickEventQuery {
+	ceq.ctx.Unique = &unique
+	return ceq
+}
+
+// Order specifies how the records should be ordered.
+func (ceq *ClickEventQuery) Order(o ...clickevent.OrderOption) *ClickEventQuery {
+	ceq.order = append(ceq.order, o...)
+	return ceq
+}
+
+// QueryBookmark chains the current query on the "bookmark" edge.
+func (ceq *ClickEventQuery) QueryBookmark() *BookmarkQuery {
+	query := (&BookmarkClient{config: ceq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := ceq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := ceq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(clickevent.Table, clickevent.FieldID, selector),
+			sqlgraph.To(bookmark.Table, bookmark.FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, clickevent.BookmarkTable, clickevent.BookmarkColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(ceq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
+// First returns the first ClickEvent entity from the query.
+// Returns a *NotFoundError when no ClickEvent was found.
+func (ceq *ClickEventQuery) First(ctx context.Context) (*ClickEvent, error) {
+	nodes, err := ceq.Limit(1).All(setContextOp(ctx, ceq.ctx, ent.OpQueryFirst))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{clickevent.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (ceq *ClickEventQuery) FirstX(ctx context.Context) *ClickEvent {
+	node, err := ceq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first ClickEvent ID from the query.
+// Returns a *NotFoundError when no ClickEvent ID was found.
+func (ceq *ClickEventQuery) FirstID(ctx context.Context) (id uuid.UUID, err error) {
+	var ids []uuid.UUID
+	if ids, err = ceq.Limit(1).IDs(setContextOp(ctx, ceq.ctx, ent.OpQueryFirstID)); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{clickevent.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (ceq *ClickEventQuery) FirstIDX(ctx context.Context) uuid.UUID {
+	id, err := ceq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single ClickEvent entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one ClickEvent entity is found.
+// Returns a *NotFoundError when no ClickEvent entities are found.
+func (ceq *ClickEventQuery) Only(ctx context.Context) (*ClickEvent, error) {
+	nodes, err := ceq.Limit(2).All(setContextOp(ctx, ceq.ctx, ent.OpQueryOnly))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{clickevent.Label}
+	default:
+		return nil, &NotSingularError{clickevent.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (ceq *ClickEventQuery) OnlyX(ctx context.Context) *ClickEvent {
+	node, err := ceq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only ClickEvent ID in the query.
+// Returns a *NotSingularError when more than one ClickEvent ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (ceq *ClickEventQuery) OnlyID(ctx context.Context) (id uuid.UUID, err error) {
+	var ids []uuid.UUID
+	if ids, err = ceq.Limit(2).IDs(setContextOp(ctx, ceq.ctx, ent.OpQueryOnlyID)); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{clickevent.Label}
+	default:
+		err = &NotSingularError{clickevent.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (ceq *ClickEventQuery) OnlyIDX(ctx context.Context) uuid.UUID {
+	id, err := ceq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of ClickEvents.
+func (ceq *ClickEventQuery) All(ctx context.Context) ([]*ClickEvent, error) {
+	ctx = setContextOp(ctx, ceq.ctx, ent.OpQueryAll)
+	if err := ceq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*ClickEvent, *ClickEventQuery]()
+	return withInterceptors[[]*ClickEvent](ctx, ceq, qr, ceq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (ceq *ClickEventQuery) AllX(ctx context.Context) []*ClickEvent {
+	nodes, err := ceq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of ClickEvent IDs.
+func (ceq *ClickEventQuery) IDs(ctx context.Context) (ids []uuid.UUID, err error) {
+	if ceq.ctx.Unique == nil && ceq.path != nil {
+		ceq.Unique(true)
+	}
+	ctx = setContextOp(ctx, ceq.ctx, ent.OpQueryIDs)
+	if err = ceq.Select(clickevent.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (ceq *ClickEventQuery) IDsX(ctx context.Context) []uuid.UUID {
+	ids, err := ceq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (ceq *ClickEventQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, ceq.ctx, ent.OpQueryCount)
+	if err := ceq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, ceq, querierCount[*ClickEventQuery](), ceq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (ceq *ClickEventQuery) CountX(ctx context.Context) int {
+	count, err := ceq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (ceq *ClickEventQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, ceq.ctx, ent.OpQueryExist)
+	switch _, err := ceq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (ceq *ClickEventQuery) ExistX(ctx context.Context) bool {
+	exist, err := ceq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the ClickEventQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (ceq *ClickEventQuery) Clone() *ClickEventQuery {
+	if ceq == nil {
+		return nil
+	}
+	return &ClickEventQuery{
+		config:       ceq.config,
+		ctx:          ceq.ctx.Clone(),
+		order:        append([]clickevent.OrderOption{}, ceq.order...),
+		inters:       append([]Interceptor{}, ceq.inters...),
+		predicates:   append([]predicate.ClickEvent{}, ceq.predicates...),
+		withBookmark: ceq.withBookmark.Clone(),
+		// clone intermediate query.
+		sql:       ceq.sql.Clone(),
+		path:      ceq.path,
+		modifiers: append([]func(*sql.Selector){}, ceq.modifiers...),
+	}
+}
+
+// WithBookmark tells the query-builder to eager-load the nodes that are connected to
+// the "bookmark" edge. The optional arguments are used to configure the query builder of the edge.
+func (ceq *ClickEventQuery) WithBookmark(opts ...func(*BookmarkQuery)) *ClickEventQuery {
+	query := (&BookmarkClient{config: ceq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	ceq.withBookmark = query
+	return ceq
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		OccurredAt time.Time `json:"occurred_at,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.ClickEvent.Query().
+//		GroupBy(clickevent.FieldOccurredAt).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (ceq *ClickEventQuery) GroupBy(field string, fields ...string) *ClickEventGroupBy {
+	ceq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &ClickEventGroupBy{build: ceq}
+	grbuild.flds = &ceq.ctx.Fields
+	grbuild.label = clickevent.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		OccurredAt time.Time `json:"occurred_at,omitempty"`
+//	}
+//
+//	client.ClickEvent.Query().
+//		Select(clickevent.FieldOccurredAt).
+//		Scan(ctx, &v)
+func (ceq *ClickEventQuery) Select(fields ...string) *ClickEventSelect {
+	ceq.ctx.Fields = append(ceq.ctx.Fields, fields...)
+	sbuild := &ClickEventSelect{ClickEventQuery: ceq}
+	sbuild.label = clickevent.Label
+	sbuild.flds, sbuild.scan = &ceq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a ClickEventSelect configured with the given aggregations.
+func (ceq *ClickEventQuery) Aggregate(fns ...AggregateFunc) *ClickEventSelect {
+	return ceq.Select().Aggregate(fns...)
+}
+
+func (ceq *ClickEventQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range ceq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, ceq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range ceq.ctx.Fields {
+		if !clickevent.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if ceq.path != nil {
+		prev, err := ceq.path(ctx)
+		if err != nil {
+			return err
+		}
+		ceq.sql = prev
+	}
+	return nil
+}
+
+func (ceq *ClickEventQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*ClickEvent, error) {
+	var (
+		nodes       = []*ClickEvent{}
+		withFKs     = ceq.withFKs
+		_spec       = ceq.querySpec()
+		loadedTypes = [1]bool{
+			ceq.withBookmark != nil,
+		}
+	)
+	if ceq.withBookmark != nil {
+		withFKs = true
+	}
+	if withFKs {
+		_spec.Node.Columns = append(_spec.Node.Columns, clickevent.ForeignKeys...)
+	}
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*ClickEvent).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &ClickEvent{config: ceq.config}
+		nodes = append(nodes, node)
+		node.Edges.loadedTypes = loadedTypes
+		return node.assignValues(columns, values)
+	}
+	if len(ceq.modifiers) > 0 {
+		_spec.Modifiers = ceq.modifiers
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, ceq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	if query := ceq.withBookmark; query != nil {
+		if err := ceq.loadBookmark(ctx, query, nodes, nil,
+			func(n *ClickEvent, e *Bookmark) { n.Edges.Bookmark = e }); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func (ceq *ClickEventQuery) loadBookmark(ctx context.Context, query *BookmarkQuery, nodes []*ClickEvent, init func(*ClickEvent), assign func(*ClickEvent, *Bookmark)) error {
+	ids := make([]uuid.UUID, 0, len(nodes))
+	nodeids := make(map[uuid.UUID][]*ClickEvent)
+	for i := range nodes {
+		if nodes[i].bookmark_clicks == nil {
+			continue
+		}
+		fk := *nodes[i].bookmark_clicks
+		if _, ok := nodeids[fk]; !ok {
+			ids = append(ids, fk)
+		}
+		nodeids[fk] = append(nodeids[fk], nodes[i])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query.Where(bookmark.IDIn(ids...))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nodeids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected foreign-key "bookmark_clicks" returned %v`, n.ID)
+		}
+		for i := range nodes {
+			assign(nodes[i], n)
+		}
+	}
+	return nil
+}
+
+func (ceq *ClickEventQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := ceq.querySpec()
+	if len(ceq.modifiers) > 0 {
+		_spec.Modifiers = ceq.modifiers
+	}
+	_spec.Node.Columns = ceq.ctx.Fields
+	if len(ceq.ctx.Fields) > 0 {
+		_spec.Unique = ceq.ctx.Unique != nil && *ceq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, ceq.driver, _spec)
+}
+
+func (ceq *ClickEventQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(clickevent.Table, clickevent.Columns, sqlgraph.NewFieldSpec(clickevent.FieldID, field.TypeUUID))
+	_spec.From = ceq.sql
+	if unique := ceq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if ceq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := ceq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, clickevent.FieldID)
+		for i := range fields {
+			if fields[i] != clickevent.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := ceq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := ceq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := ceq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := ceq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (ceq *ClickEventQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(ceq.driver.Dialect())
+	t1 := builder.Table(clickevent.Table)
+	columns := ceq.ctx.Fields
+	if len(columns) == 0 {
+		columns = clickevent.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if ceq.sql != nil {
+		selector = ceq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if ceq.ctx.Unique != nil && *ceq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, m := range ceq.modifiers {
+		m(selector)
+	}
+	for _, p := range ceq.predicates {
+		p(selector)
+	}
+	for _, p := range ceq.order {
+		p(selector)
+	}
+	if offset := ceq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := ceq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (ceq *ClickEventQuery) Modify(modifiers ...func(s *sql.Selector)) *ClickEventSelect {
+	ceq.modifiers = append(ceq.modifiers, modifiers...)
+	return ceq.Select()
+}
+
+// ClickEventGroupBy is the group-by builder for ClickEvent entities.
+type ClickEventGroupBy struct {
+	selector
+	build *ClickEventQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (cegb *ClickEventGroupBy) Aggregate(fns ...AggregateFunc) *ClickEventGroupBy {
+	cegb.fns = append(cegb.fns, fns...)
+	return cegb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (cegb *ClickEventGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, cegb.build.ctx, ent.OpQueryGroupBy)
+	if err := cegb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*ClickEventQuery, *ClickEventGroupBy](ctx, cegb.build, cegb, cegb.build.inters, v)
+}
+
+func (cegb *ClickEventGroupBy) sqlScan(ctx context.Context, root *ClickEventQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(cegb.fns))
+	for _, fn := range cegb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*cegb.flds)+len(cegb.fns))
+		for _, f := range *cegb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*cegb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := cegb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// ClickEventSelect is the builder for selecting fields of ClickEvent entities.
+type ClickEventSelect struct {
+	*ClickEventQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (ces *ClickEventSelect) Aggregate(fns ...AggregateFunc) *ClickEventSelect {
+	ces.fns = append(ces.fns, fns...)
+	return ces
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ces *ClickEventSelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ces.ctx, ent.OpQuerySelect)
+	if err := ces.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*ClickEventQuery, *ClickEventSelect](ctx, ces.ClickEventQuery, ces, ces.inters, v)
+}
+
+func (ces *ClickEventSelect) sqlScan(ctx context.Context, root *ClickEventQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(ces.fns))
+	for _, fn := range ces.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*ces.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ces.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (ces *ClickEventSelect) Modify(modifiers ...func(s *sql.Selector)) *ClickEventSelect {
+	ces.modifiers = append(ces.modifiers, modifiers...)
+	return ces
+}
@@ -0,0 +1,213 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"bookmark-shortener/ent/clickevent"
+	"bookmark-shortener/ent/predicate"
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// ClickEventUpdate is the builder for updating ClickEvent entities.
+type ClickEventUpdate struct {
+	config
+	hooks     []Hook
+	mutation  *ClickEventMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Where appends a list predicates to the ClickEventUpdate builder.
+func (ceu *ClickEventUpdate) Where(ps ...predicate.ClickEvent) *ClickEventUpdate {
+	ceu.mutation.Where(ps...)
+	return ceu
+}
+
+// Mutation returns the ClickEventMutation object of the builder.
+func (ceu *ClickEventUpdate) Mutation() *ClickEventMutation {
+	return ceu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (ceu *ClickEventUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, ceu.sqlSave, ceu.mutation, ceu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ceu *ClickEventUpdate) SaveX(ctx context.Context) int {
+	affected, err := ceu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (ceu *ClickEventUpdate) Exec(ctx context.Context) error {
+	_, err := ceu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ceu *ClickEventUpdate) ExecX(ctx context.Context) {
+	if err := ceu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ceu *ClickEventUpdate) check() error {
+	if ceu.mutation.BookmarkCleared() && len(ceu.mutation.BookmarkIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "ClickEvent.bookmark"`)
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (ceu *ClickEventUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *ClickEventUpdate {
+	ceu.modifiers = append(ceu.modifiers, modifiers...)
+	return ceu
+}
+
+func (ceu *ClickEventUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := ceu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(clickevent.Table, clickevent.Columns, sqlgraph.NewFieldSpec(clickevent.FieldID, field.TypeUUID))
+	if ps := ceu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	_spec.AddModifiers(ceu.modifiers...)
+	if n, err = sqlgraph.UpdateNodes(ctx, ceu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{clickevent.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	ceu.mutation.done = true
+	return n, nil
+}
+
+// ClickEventUpdateOne is the builder for updating a single ClickEvent entity.
+type ClickEventUpdateOne struct {
+	config
+	fields    []string
+	hooks     []Hook
+	mutation  *ClickEventMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Mutation returns the ClickEventMutation object of the builder.
+func (ceuo *ClickEventUpdateOne) Mutation() *ClickEventMutation {
+	return ceuo.mutation
+}
+
+// Where appends a list predicates to the ClickEventUpdate builder.
+func (ceuo *ClickEventUpdateOne) Where(ps ...predicate.ClickEvent) *ClickEventUpdateOne {
+	ceuo.mutation.Where(ps...)
+	return ceuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (ceuo *ClickEventUpdateOne) Select(field string, fields ...string) *ClickEventUpdateOne {
+	ceuo.fields = append([]string{field}, fields...)
+	return ceuo
+}
+
+// Save executes the query and returns the updated ClickEvent entity.
+func (ceuo *ClickEventUpdateOne) Save(ctx context.Context) (*ClickEvent, error) {
+	return withHooks(ctx, ceuo.sqlSave, ceuo.mutation, ceuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ceuo *ClickEventUpdateOne) SaveX(ctx context.Context) *ClickEvent {
+	node, err := ceuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (ceuo *ClickEventUpdateOne) Exec(ctx context.Context) error {
+	_, err := ceuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ceuo *ClickEventUpdateOne) ExecX(ctx context.Context) {
+	if err := ceuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ceuo *ClickEventUpdateOne) check() error {
+	if ceuo.mutation.BookmarkCleared() && len(ceuo.mutation.BookmarkIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "ClickEvent.bookmark"`)
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (ceuo *ClickEventUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *ClickEventUpdateOne {
+	ceuo.modifiers = append(ceuo.modifiers, modifiers...)
+	return ceuo
+}
+
+func (ceuo *ClickEventUpdateOne) sqlSave(ctx context.Context) (_node *ClickEvent, err error) {
+	if err := ceuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(clickevent.Table, clickevent.Columns, sqlgraph.NewFieldSpec(clickevent.FieldID, field.TypeUUID))
+	id, ok := ceuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "ClickEvent.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := ceuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, clickevent.FieldID)
+		for _, f := range fields {
+			if !clickevent.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != clickevent.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := ceuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	_spec.AddModifiers(ceuo.modifiers...)
+	_node = &ClickEvent{config: ceuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, ceuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{clickevent.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	ceuo.mutation.done = true
+	return _node, nil
+}
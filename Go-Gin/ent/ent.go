@@ -4,6 +4,10 @@ package ent
 
 import (
 	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/ent/clickevent"
+	"bookmark-shortener/ent/passwordresettoken"
+	"bookmark-shortener/ent/recoverycode"
+	"bookmark-shortener/ent/refreshtoken"
 	"bookmark-shortener/ent/user"
 	"context"
 	"errors"
@@ -74,8 +78,12 @@ var (
 func checkColumn(table, column string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			bookmark.Table: bookmark.ValidColumn,
-			user.Table:     user.ValidColumn,
+			bookmark.Table:           bookmark.ValidColumn,
+			clickevent.Table:         clickevent.ValidColumn,
+			passwordresettoken.Table: passwordresettoken.ValidColumn,
+			recoverycode.Table:       recoverycode.ValidColumn,
+			refreshtoken.Table:       refreshtoken.ValidColumn,
+			user.Table:               user.ValidColumn,
 		})
 	})
 	return columnCheck(table, column)
@@ -1 +1,3 @@
 package ent
+
+//go:generate go run -mod=mod entgo.io/ent/cmd/ent generate --feature sql/execquery,sql/modifier,sql/versioned-migration ./schema
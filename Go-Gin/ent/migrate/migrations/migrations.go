@@ -0,0 +1,38 @@
+// Package migrations embeds the versioned SQL migrations generated from the
+// Ent schema with `go run ./cmd/migrate diff`, one directory per dialect.
+// Files follow the golang-migrate layout: <version>_<name>.up.sql and
+// <version>_<name>.down.sql.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"entgo.io/ent/dialect"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Dir returns the source directory of a dialect's migrations, relative to
+// the module root.
+func Dir(dialectName string) (string, error) {
+	switch dialectName {
+	case dialect.Postgres:
+		return "ent/migrate/migrations/postgres", nil
+	case dialect.SQLite:
+		return "ent/migrate/migrations/sqlite", nil
+	default:
+		return "", fmt.Errorf("no migrations for dialect %q", dialectName)
+	}
+}
+
+// FS returns the embedded migrations of a dialect.
+func FS(dialectName string) (fs.FS, error) {
+	dir, err := Dir(dialectName)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Sub(files, dir[len("ent/migrate/migrations/"):])
+}
@@ -0,0 +1,4 @@
+-- reverse: create "bookmarks" table
+DROP TABLE "bookmarks";
+-- reverse: create "users" table
+DROP TABLE "users";
@@ -0,0 +1,8 @@
+-- create "users" table
+CREATE TABLE "users" ("id" uuid NOT NULL, "email" character varying NOT NULL, "password_hash" character varying NOT NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "users_email_key" to table: "users"
+CREATE UNIQUE INDEX "users_email_key" ON "users" ("email");
+-- create "bookmarks" table
+CREATE TABLE "bookmarks" ("id" uuid NOT NULL, "title" character varying NOT NULL, "url" character varying NOT NULL, "short_code" character varying NOT NULL, "visit_count" bigint NOT NULL DEFAULT 0, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, "user_bookmarks" uuid NULL, PRIMARY KEY ("id"), CONSTRAINT "bookmarks_users_bookmarks" FOREIGN KEY ("user_bookmarks") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE SET NULL);
+-- create index "bookmarks_short_code_key" to table: "bookmarks"
+CREATE UNIQUE INDEX "bookmarks_short_code_key" ON "bookmarks" ("short_code");
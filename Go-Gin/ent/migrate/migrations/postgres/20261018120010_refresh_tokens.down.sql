@@ -0,0 +1,2 @@
+-- reverse: create "refresh_tokens" table
+DROP TABLE "refresh_tokens";
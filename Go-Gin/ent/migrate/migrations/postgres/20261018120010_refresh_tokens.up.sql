@@ -0,0 +1,8 @@
+-- create "refresh_tokens" table
+CREATE TABLE "refresh_tokens" ("id" uuid NOT NULL, "token_hash" character varying NOT NULL, "family_id" uuid NOT NULL, "access_jti" character varying NOT NULL, "expires_at" timestamptz NOT NULL, "used_at" timestamptz NULL, "revoked_at" timestamptz NULL, "created_at" timestamptz NOT NULL, "user_refresh_tokens" uuid NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "refresh_tokens_users_refresh_tokens" FOREIGN KEY ("user_refresh_tokens") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION);
+-- create index "refresh_tokens_token_hash_key" to table: "refresh_tokens"
+CREATE UNIQUE INDEX "refresh_tokens_token_hash_key" ON "refresh_tokens" ("token_hash");
+-- create index "refreshtoken_access_jti" to table: "refresh_tokens"
+CREATE INDEX "refreshtoken_access_jti" ON "refresh_tokens" ("access_jti");
+-- create index "refreshtoken_family_id" to table: "refresh_tokens"
+CREATE INDEX "refreshtoken_family_id" ON "refresh_tokens" ("family_id");
@@ -0,0 +1,2 @@
+-- reverse: modify "bookmarks" table
+ALTER TABLE "bookmarks" DROP COLUMN "max_visits", DROP COLUMN "expires_at";
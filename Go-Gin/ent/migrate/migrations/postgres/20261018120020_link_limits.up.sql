@@ -0,0 +1,2 @@
+-- modify "bookmarks" table
+ALTER TABLE "bookmarks" ADD COLUMN "expires_at" timestamptz NULL, ADD COLUMN "max_visits" bigint NULL;
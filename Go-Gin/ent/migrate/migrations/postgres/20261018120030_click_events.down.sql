@@ -0,0 +1,2 @@
+-- reverse: create "click_events" table
+DROP TABLE "click_events";
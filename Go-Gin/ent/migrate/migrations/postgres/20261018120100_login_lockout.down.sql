@@ -0,0 +1,2 @@
+-- reverse: modify "users" table
+ALTER TABLE "users" DROP COLUMN "locked_until", DROP COLUMN "last_failed_login_at", DROP COLUMN "failed_login_attempts";
@@ -0,0 +1,2 @@
+-- reverse: create "password_reset_tokens" table
+DROP TABLE "password_reset_tokens";
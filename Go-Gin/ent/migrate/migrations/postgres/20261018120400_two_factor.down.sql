@@ -0,0 +1,4 @@
+-- reverse: create "recovery_codes" table
+DROP TABLE "recovery_codes";
+-- reverse: modify "users" table
+ALTER TABLE "users" DROP COLUMN "totp_last_step", DROP COLUMN "totp_enabled_at", DROP COLUMN "totp_secret";
@@ -0,0 +1,8 @@
+-- reverse: create index "users_email_key" to table: "users"
+DROP INDEX `users_email_key`;
+-- reverse: create "users" table
+DROP TABLE `users`;
+-- reverse: create index "bookmarks_short_code_key" to table: "bookmarks"
+DROP INDEX `bookmarks_short_code_key`;
+-- reverse: create "bookmarks" table
+DROP TABLE `bookmarks`;
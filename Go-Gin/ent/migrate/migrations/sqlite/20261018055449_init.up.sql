@@ -0,0 +1,8 @@
+-- create "bookmarks" table
+CREATE TABLE `bookmarks` (`id` uuid NOT NULL, `title` text NOT NULL, `url` text NOT NULL, `short_code` text NOT NULL, `visit_count` integer NOT NULL DEFAULT (0), `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, `user_bookmarks` uuid NULL, PRIMARY KEY (`id`), CONSTRAINT `bookmarks_users_bookmarks` FOREIGN KEY (`user_bookmarks`) REFERENCES `users` (`id`) ON DELETE SET NULL);
+-- create index "bookmarks_short_code_key" to table: "bookmarks"
+CREATE UNIQUE INDEX `bookmarks_short_code_key` ON `bookmarks` (`short_code`);
+-- create "users" table
+CREATE TABLE `users` (`id` uuid NOT NULL, `email` text NOT NULL, `password_hash` text NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`));
+-- create index "users_email_key" to table: "users"
+CREATE UNIQUE INDEX `users_email_key` ON `users` (`email`);
@@ -0,0 +1,8 @@
+-- reverse: create index "refreshtoken_access_jti" to table: "refresh_tokens"
+DROP INDEX `refreshtoken_access_jti`;
+-- reverse: create index "refreshtoken_family_id" to table: "refresh_tokens"
+DROP INDEX `refreshtoken_family_id`;
+-- reverse: create index "refresh_tokens_token_hash_key" to table: "refresh_tokens"
+DROP INDEX `refresh_tokens_token_hash_key`;
+-- reverse: create "refresh_tokens" table
+DROP TABLE `refresh_tokens`;
@@ -0,0 +1,4 @@
+-- reverse: add column "max_visits" to table: "bookmarks"
+ALTER TABLE `bookmarks` DROP COLUMN `max_visits`;
+-- reverse: add column "expires_at" to table: "bookmarks"
+ALTER TABLE `bookmarks` DROP COLUMN `expires_at`;
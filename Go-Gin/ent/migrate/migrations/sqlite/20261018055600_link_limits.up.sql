@@ -0,0 +1,4 @@
+-- add column "expires_at" to table: "bookmarks"
+ALTER TABLE `bookmarks` ADD COLUMN `expires_at` datetime NULL;
+-- add column "max_visits" to table: "bookmarks"
+ALTER TABLE `bookmarks` ADD COLUMN `max_visits` integer NULL;
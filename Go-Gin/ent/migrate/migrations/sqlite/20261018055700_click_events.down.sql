@@ -0,0 +1,4 @@
+-- reverse: create index "clickevent_occurred_at_bookmark_clicks" to table: "click_events"
+DROP INDEX `clickevent_occurred_at_bookmark_clicks`;
+-- reverse: create "click_events" table
+DROP TABLE `click_events`;
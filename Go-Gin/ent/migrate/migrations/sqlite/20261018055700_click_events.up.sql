@@ -0,0 +1,4 @@
+-- create "click_events" table
+CREATE TABLE `click_events` (`id` uuid NOT NULL, `occurred_at` datetime NOT NULL, `referrer_host` text NOT NULL DEFAULT (''), `ua_family` text NOT NULL DEFAULT (''), `visitor_hash` text NOT NULL, `country` text NOT NULL DEFAULT (''), `bookmark_clicks` uuid NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `click_events_bookmarks_clicks` FOREIGN KEY (`bookmark_clicks`) REFERENCES `bookmarks` (`id`) ON DELETE CASCADE);
+-- create index "clickevent_occurred_at_bookmark_clicks" to table: "click_events"
+CREATE INDEX `clickevent_occurred_at_bookmark_clicks` ON `click_events` (`occurred_at`, `bookmark_clicks`);
@@ -0,0 +1,4 @@
+-- reverse: add columns to "users" table
+ALTER TABLE `users` DROP COLUMN `locked_until`;
+ALTER TABLE `users` DROP COLUMN `last_failed_login_at`;
+ALTER TABLE `users` DROP COLUMN `failed_login_attempts`;
@@ -0,0 +1,4 @@
+-- add columns to "users" table
+ALTER TABLE `users` ADD COLUMN `failed_login_attempts` integer NOT NULL DEFAULT (0);
+ALTER TABLE `users` ADD COLUMN `last_failed_login_at` datetime NULL;
+ALTER TABLE `users` ADD COLUMN `locked_until` datetime NULL;
@@ -0,0 +1,4 @@
+-- reverse: create index "password_reset_tokens_token_hash_key" to table: "password_reset_tokens"
+DROP INDEX `password_reset_tokens_token_hash_key`;
+-- reverse: create "password_reset_tokens" table
+DROP TABLE `password_reset_tokens`;
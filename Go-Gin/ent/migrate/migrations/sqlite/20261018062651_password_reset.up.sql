@@ -0,0 +1,4 @@
+-- create "password_reset_tokens" table
+CREATE TABLE `password_reset_tokens` (`id` uuid NOT NULL, `token_hash` text NOT NULL, `expires_at` datetime NOT NULL, `used_at` datetime NULL, `created_at` datetime NOT NULL, `user_password_reset_tokens` uuid NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `password_reset_tokens_users_password_reset_tokens` FOREIGN KEY (`user_password_reset_tokens`) REFERENCES `users` (`id`) ON DELETE CASCADE);
+-- create index "password_reset_tokens_token_hash_key" to table: "password_reset_tokens"
+CREATE UNIQUE INDEX `password_reset_tokens_token_hash_key` ON `password_reset_tokens` (`token_hash`);
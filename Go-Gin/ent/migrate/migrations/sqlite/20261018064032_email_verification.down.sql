@@ -0,0 +1,2 @@
+-- reverse: add column "email_verified_at" to table: "users"
+ALTER TABLE `users` DROP COLUMN `email_verified_at`;
@@ -0,0 +1,2 @@
+-- add column "email_verified_at" to table: "users"
+ALTER TABLE `users` ADD COLUMN `email_verified_at` datetime NULL;
@@ -0,0 +1,8 @@
+-- reverse: create index "recovery_codes_code_hash_key" to table: "recovery_codes"
+DROP INDEX `recovery_codes_code_hash_key`;
+-- reverse: create "recovery_codes" table
+DROP TABLE `recovery_codes`;
+-- reverse: add columns to "users" table
+ALTER TABLE `users` DROP COLUMN `totp_last_step`;
+ALTER TABLE `users` DROP COLUMN `totp_enabled_at`;
+ALTER TABLE `users` DROP COLUMN `totp_secret`;
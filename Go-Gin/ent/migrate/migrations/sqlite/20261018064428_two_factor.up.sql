@@ -0,0 +1,8 @@
+-- add columns to "users" table
+ALTER TABLE `users` ADD COLUMN `totp_secret` text NULL;
+ALTER TABLE `users` ADD COLUMN `totp_enabled_at` datetime NULL;
+ALTER TABLE `users` ADD COLUMN `totp_last_step` integer NOT NULL DEFAULT (0);
+-- create "recovery_codes" table
+CREATE TABLE `recovery_codes` (`id` uuid NOT NULL, `code_hash` text NOT NULL, `used_at` datetime NULL, `created_at` datetime NOT NULL, `user_recovery_codes` uuid NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `recovery_codes_users_recovery_codes` FOREIGN KEY (`user_recovery_codes`) REFERENCES `users` (`id`) ON DELETE CASCADE);
+-- create index "recovery_codes_code_hash_key" to table: "recovery_codes"
+CREATE UNIQUE INDEX `recovery_codes_code_hash_key` ON `recovery_codes` (`code_hash`);
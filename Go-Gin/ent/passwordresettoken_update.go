@@ -0,0 +1,394 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"bookmark-shortener/ent/passwordresettoken"
+	"bookmark-shortener/ent/predicate"
+	"bookmark-shortener/ent/user"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/google/uuid"
+)
+
+// PasswordResetTokenUpdate is the builder for updating PasswordResetToken entities.
+type PasswordResetTokenUpdate struct {
+	config
+	hooks     []Hook
+	mutation  *PasswordResetTokenMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Where appends a list predicates to the PasswordResetTokenUpdate builder.
+func (prtu *PasswordResetTokenUpdate) Where(ps ...predicate.PasswordResetToken) *PasswordResetTokenUpdate {
+	prtu.mutation.Where(ps...)
+	return prtu
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (prtu *PasswordResetTokenUpdate) SetTokenHash(s string) *PasswordResetTokenUpdate {
+	prtu.mutation.SetTokenHash(s)
+	return prtu
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (prtu *PasswordResetTokenUpdate) SetNillableTokenHash(s *string) *PasswordResetTokenUpdate {
+	if s != nil {
+		prtu.SetTokenHash(*s)
+	}
+	return prtu
+}
+
+// SetUsedAt sets the "used_at" field.
+func (prtu *PasswordResetTokenUpdate) SetUsedAt(t time.Time) *PasswordResetTokenUpdate {
+	prtu.mutation.SetUsedAt(t)
+	return prtu
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (prtu *PasswordResetTokenUpdate) SetNillableUsedAt(t *time.Time) *PasswordResetTokenUpdate {
+	if t != nil {
+		prtu.SetUsedAt(*t)
+	}
+	return prtu
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (prtu *PasswordResetTokenUpdate) ClearUsedAt() *PasswordResetTokenUpdate {
+	prtu.mutation.ClearUsedAt()
+	return prtu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (prtu *PasswordResetTokenUpdate) SetUserID(id uuid.UUID) *PasswordResetTokenUpdate {
+	prtu.mutation.SetUserID(id)
+	return prtu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (prtu *PasswordResetTokenUpdate) SetUser(u *User) *PasswordResetTokenUpdate {
+	return prtu.SetUserID(u.ID)
+}
+
+// Mutation returns the PasswordResetTokenMutation object of the builder.
+func (prtu *PasswordResetTokenUpdate) Mutation() *PasswordResetTokenMutation {
+	return prtu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (prtu *PasswordResetTokenUpdate) ClearUser() *PasswordResetTokenUpdate {
+	prtu.mutation.ClearUser()
+	return prtu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (prtu *PasswordResetTokenUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, prtu.sqlSave, prtu.mutation, prtu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (prtu *PasswordResetTokenUpdate) SaveX(ctx context.Context) int {
+	affected, err := prtu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (prtu *PasswordResetTokenUpdate) Exec(ctx context.Context) error {
+	_, err := prtu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (prtu *PasswordResetTokenUpdate) ExecX(ctx context.Context) {
+	if err := prtu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (prtu *PasswordResetTokenUpdate) check() error {
+	if prtu.mutation.UserCleared() && len(prtu.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "PasswordResetToken.user"`)
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (prtu *PasswordResetTokenUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *PasswordResetTokenUpdate {
+	prtu.modifiers = append(prtu.modifiers, modifiers...)
+	return prtu
+}
+
+func (prtu *PasswordResetTokenUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := prtu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(passwordresettoken.Table, passwordresettoken.Columns, sqlgraph.NewFieldSpec(passwordresettoken.FieldID, field.TypeUUID))
+	if ps := prtu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := prtu.mutation.TokenHash(); ok {
+		_spec.SetField(passwordresettoken.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := prtu.mutation.UsedAt(); ok {
+		_spec.SetField(passwordresettoken.FieldUsedAt, field.TypeTime, value)
+	}
+	if prtu.mutation.UsedAtCleared() {
+		_spec.ClearField(passwordresettoken.FieldUsedAt, field.TypeTime)
+	}
+	if prtu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   passwordresettoken.UserTable,
+			Columns: []string{passwordresettoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := prtu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   passwordresettoken.UserTable,
+			Columns: []string{passwordresettoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_spec.AddModifiers(prtu.modifiers...)
+	if n, err = sqlgraph.UpdateNodes(ctx, prtu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{passwordresettoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	prtu.mutation.done = true
+	return n, nil
+}
+
+// PasswordResetTokenUpdateOne is the builder for updating a single PasswordResetToken entity.
+type PasswordResetTokenUpdateOne struct {
+	config
+	fields    []string
+	hooks     []Hook
+	mutation  *PasswordResetTokenMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (prtuo *PasswordResetTokenUpdateOne) SetTokenHash(s string) *PasswordResetTokenUpdateOne {
+	prtuo.mutation.SetTokenHash(s)
+	return prtuo
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (prtuo *PasswordResetTokenUpdateOne) SetNillableTokenHash(s *string) *PasswordResetTokenUpdateOne {
+	if s != nil {
+		prtuo.SetTokenHash(*s)
+	}
+	return prtuo
+}
+
+// SetUsedAt sets the "used_at" field.
+func (prtuo *PasswordResetTokenUpdateOne) SetUsedAt(t time.Time) *PasswordResetTokenUpdateOne {
+	prtuo.mutation.SetUsedAt(t)
+	return prtuo
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (prtuo *PasswordResetTokenUpdateOne) SetNillableUsedAt(t *time.Time) *PasswordResetTokenUpdateOne {
+	if t != nil {
+		prtuo.SetUsedAt(*t)
+	}
+	return prtuo
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (prtuo *PasswordResetTokenUpdateOne) ClearUsedAt() *PasswordResetTokenUpdateOne {
+	prtuo.mutation.ClearUsedAt()
+	return prtuo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (prtuo *PasswordResetTokenUpdateOne) SetUserID(id uuid.UUID) *PasswordResetTokenUpdateOne {
+	prtuo.mutation.SetUserID(id)
+	return prtuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (prtuo *PasswordResetTokenUpdateOne) SetUser(u *User) *PasswordResetTokenUpdateOne {
+	return prtuo.SetUserID(u.ID)
+}
+
+// Mutation returns the PasswordResetTokenMutation object of the builder.
+func (prtuo *PasswordResetTokenUpdateOne) Mutation() *PasswordResetTokenMutation {
+	return prtuo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (prtuo *PasswordResetTokenUpdateOne) ClearUser() *PasswordResetTokenUpdateOne {
+	prtuo.mutation.ClearUser()
+	return prtuo
+}
+
+// Where appends a list predicates to the PasswordResetTokenUpdate builder.
+func (prtuo *PasswordResetTokenUpdateOne) Where(ps ...predicate.PasswordResetToken) *PasswordResetTokenUpdateOne {
+	prtuo.mutation.Where(ps...)
+	return prtuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (prtuo *PasswordResetTokenUpdateOne) Select(field string, fields ...string) *PasswordResetTokenUpdateOne {
+	prtuo.fields = append([]string{field}, fields...)
+	return prtuo
+}
+
+// Save executes the query and returns the updated PasswordResetToken entity.
+func (prtuo *PasswordResetTokenUpdateOne) Save(ctx context.Context) (*PasswordResetToken, error) {
+	return withHooks(ctx, prtuo.sqlSave, prtuo.mutation, prtuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (prtuo *PasswordResetTokenUpdateOne) SaveX(ctx context.Context) *PasswordResetToken {
+	node, err := prtuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (prtuo *PasswordResetTokenUpdateOne) Exec(ctx context.Context) error {
+	_, err := prtuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (prtuo *PasswordResetTokenUpdateOne) ExecX(ctx context.Context) {
+	if err := prtuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (prtuo *PasswordResetTokenUpdateOne) check() error {
+	if prtuo.mutation.UserCleared() && len(prtuo.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "PasswordResetToken.user"`)
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (prtuo *PasswordResetTokenUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *PasswordResetTokenUpdateOne {
+	prtuo.modifiers = append(prtuo.modifiers, modifiers...)
+	return prtuo
+}
+
+func (prtuo *PasswordResetTokenUpdateOne) sqlSave(ctx context.Context) (_node *PasswordResetToken, err error) {
+	if err := prtuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(passwordresettoken.Table, passwordresettoken.Columns, sqlgraph.NewFieldSpec(passwordresettoken.FieldID, field.TypeUUID))
+	id, ok := prtuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "PasswordResetToken.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := prtuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, passwordresettoken.FieldID)
+		for _, f := range fields {
+			if !passwordresettoken.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != passwordresettoken.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := prtuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := prtuo.mutation.TokenHash(); ok {
+		_spec.SetField(passwordresettoken.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := prtuo.mutation.UsedAt(); ok {
+		_spec.SetField(passwordresettoken.FieldUsedAt, field.TypeTime, value)
+	}
+	if prtuo.mutation.UsedAtCleared() {
+		_spec.ClearField(passwordresettoken.FieldUsedAt, field.TypeTime)
+	}
+	if prtuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   passwordresettoken.UserTable,
+			Columns: []string{passwordresettoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := prtuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   passwordresettoken.UserTable,
+			Columns: []string{passwordresettoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_spec.AddModifiers(prtuo.modifiers...)
+	_node = &PasswordResetToken{config: prtuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, prtuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{passwordresettoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	prtuo.mutation.done = true
+	return _node, nil
+}
@@ -4,6 +4,10 @@ package ent
 
 import (
 	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/ent/clickevent"
+	"bookmark-shortener/ent/passwordresettoken"
+	"bookmark-shortener/ent/recoverycode"
+	"bookmark-shortener/ent/refreshtoken"
 	"bookmark-shortener/ent/schema"
 	"bookmark-shortener/ent/user"
 	"time"
@@ -21,12 +25,16 @@ func init() {
 	bookmarkDescVisitCount := bookmarkFields[4].Descriptor()
 	// bookmark.DefaultVisitCount holds the default value on creation for the visit_count field.
 	bookmark.DefaultVisitCount = bookmarkDescVisitCount.Default.(int)
+	// bookmarkDescMaxVisits is the schema descriptor for max_visits field.
+	bookmarkDescMaxVisits := bookmarkFields[6].Descriptor()
+	// bookmark.MaxVisitsValidator is a validator for the "max_visits" field. It is called by the builders before save.
+	bookmark.MaxVisitsValidator = bookmarkDescMaxVisits.Validators[0].(func(int) error)
 	// bookmarkDescCreatedAt is the schema descriptor for created_at field.
-	bookmarkDescCreatedAt := bookmarkFields[5].Descriptor()
+	bookmarkDescCreatedAt := bookmarkFields[7].Descriptor()
 	// bookmark.DefaultCreatedAt holds the default value on creation for the created_at field.
 	bookmark.DefaultCreatedAt = bookmarkDescCreatedAt.Default.(func() time.Time)
 	// bookmarkDescUpdatedAt is the schema descriptor for updated_at field.
-	bookmarkDescUpdatedAt := bookmarkFields[6].Descriptor()
+	bookmarkDescUpdatedAt := bookmarkFields[8].Descriptor()
 	// bookmark.DefaultUpdatedAt holds the default value on creation for the updated_at field.
 	bookmark.DefaultUpdatedAt = bookmarkDescUpdatedAt.Default.(func() time.Time)
 	// bookmark.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
@@ -35,14 +43,74 @@ func init() {
 	bookmarkDescID := bookmarkFields[0].Descriptor()
 	// bookmark.DefaultID holds the default value on creation for the id field.
 	bookmark.DefaultID = bookmarkDescID.Default.(func() uuid.UUID)
+	clickeventFields := schema.ClickEvent{}.Fields()
+	_ = clickeventFields
+	// clickeventDescOccurredAt is the schema descriptor for occurred_at field.
+	clickeventDescOccurredAt := clickeventFields[1].Descriptor()
+	// clickevent.DefaultOccurredAt holds the default value on creation for the occurred_at field.
+	clickevent.DefaultOccurredAt = clickeventDescOccurredAt.Default.(func() time.Time)
+	// clickeventDescReferrerHost is the schema descriptor for referrer_host field.
+	clickeventDescReferrerHost := clickeventFields[2].Descriptor()
+	// clickevent.DefaultReferrerHost holds the default value on creation for the referrer_host field.
+	clickevent.DefaultReferrerHost = clickeventDescReferrerHost.Default.(string)
+	// clickeventDescUaFamily is the schema descriptor for ua_family field.
+	clickeventDescUaFamily := clickeventFields[3].Descriptor()
+	// clickevent.DefaultUaFamily holds the default value on creation for the ua_family field.
+	clickevent.DefaultUaFamily = clickeventDescUaFamily.Default.(string)
+	// clickeventDescCountry is the schema descriptor for country field.
+	clickeventDescCountry := clickeventFields[5].Descriptor()
+	// clickevent.DefaultCountry holds the default value on creation for the country field.
+	clickevent.DefaultCountry = clickeventDescCountry.Default.(string)
+	// clickeventDescID is the schema descriptor for id field.
+	clickeventDescID := clickeventFields[0].Descriptor()
+	// clickevent.DefaultID holds the default value on creation for the id field.
+	clickevent.DefaultID = clickeventDescID.Default.(func() uuid.UUID)
+	passwordresettokenFields := schema.PasswordResetToken{}.Fields()
+	_ = passwordresettokenFields
+	// passwordresettokenDescCreatedAt is the schema descriptor for created_at field.
+	passwordresettokenDescCreatedAt := passwordresettokenFields[4].Descriptor()
+	// passwordresettoken.DefaultCreatedAt holds the default value on creation for the created_at field.
+	passwordresettoken.DefaultCreatedAt = passwordresettokenDescCreatedAt.Default.(func() time.Time)
+	// passwordresettokenDescID is the schema descriptor for id field.
+	passwordresettokenDescID := passwordresettokenFields[0].Descriptor()
+	// passwordresettoken.DefaultID holds the default value on creation for the id field.
+	passwordresettoken.DefaultID = passwordresettokenDescID.Default.(func() uuid.UUID)
+	recoverycodeFields := schema.RecoveryCode{}.Fields()
+	_ = recoverycodeFields
+	// recoverycodeDescCreatedAt is the schema descriptor for created_at field.
+	recoverycodeDescCreatedAt := recoverycodeFields[3].Descriptor()
+	// recoverycode.DefaultCreatedAt holds the default value on creation for the created_at field.
+	recoverycode.DefaultCreatedAt = recoverycodeDescCreatedAt.Default.(func() time.Time)
+	// recoverycodeDescID is the schema descriptor for id field.
+	recoverycodeDescID := recoverycodeFields[0].Descriptor()
+	// recoverycode.DefaultID holds the default value on creation for the id field.
+	recoverycode.DefaultID = recoverycodeDescID.Default.(func() uuid.UUID)
+	refreshtokenFields := schema.RefreshToken{}.Fields()
+	_ = refreshtokenFields
+	// refreshtokenDescCreatedAt is the schema descriptor for created_at field.
+	refreshtokenDescCreatedAt := refreshtokenFields[7].Descriptor()
+	// refreshtoken.DefaultCreatedAt holds the default value on creation for the created_at field.
+	refreshtoken.DefaultCreatedAt = refreshtokenDescCreatedAt.Default.(func() time.Time)
+	// refreshtokenDescID is the schema descriptor for id field.
+	refreshtokenDescID := refreshtokenFields[0].Descriptor()
+	// refreshtoken.DefaultID holds the default value on creation for the id field.
+	refreshtoken.DefaultID = refreshtokenDescID.Default.(func() uuid.UUID)
 	userFields := schema.User{}.Fields()
 	_ = userFields
+	// userDescFailedLoginAttempts is the schema descriptor for failed_login_attempts field.
+	userDescFailedLoginAttempts := userFields[3].Descriptor()
+	// user.DefaultFailedLoginAttempts holds the default value on creation for the failed_login_attempts field.
+	user.DefaultFailedLoginAttempts = userDescFailedLoginAttempts.Default.(int)
+	// userDescTotpLastStep is the schema descriptor for totp_last_step field.
+	userDescTotpLastStep := userFields[9].Descriptor()
+	// user.DefaultTotpLastStep holds the default value on creation for the totp_last_step field.
+	user.DefaultTotpLastStep = userDescTotpLastStep.Default.(int64)
 	// userDescCreatedAt is the schema descriptor for created_at field.
-	userDescCreatedAt := userFields[3].Descriptor()
+	userDescCreatedAt := userFields[10].Descriptor()
 	// user.DefaultCreatedAt holds the default value on creation for the created_at field.
 	user.DefaultCreatedAt = userDescCreatedAt.Default.(func() time.Time)
 	// userDescUpdatedAt is the schema descriptor for updated_at field.
-	userDescUpdatedAt := userFields[4].Descriptor()
+	userDescUpdatedAt := userFields[11].Descriptor()
 	// user.DefaultUpdatedAt holds the default value on creation for the updated_at field.
 	user.DefaultUpdatedAt = userDescUpdatedAt.Default.(func() time.Time)
 	// user.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
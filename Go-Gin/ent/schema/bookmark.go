@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 	"github.com/google/uuid"
@@ -20,6 +21,8 @@ func (Bookmark) Fields() []ent.Field {
 		field.String("url"),
 		field.String("short_code").Unique(),
 		field.Int("visit_count").Default(0),
+		field.Time("expires_at").Optional().Nillable(),
+		field.Int("max_visits").Optional().Nillable().Positive(),
 		field.Time("created_at").Default(time.Now),
 		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
 	}
@@ -30,5 +33,7 @@ func (Bookmark) Edges() []ent.Edge {
 		edge.From("owner", User.Type).
 			Ref("bookmarks").
 			Unique(),
+		edge.To("clicks", ClickEvent.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
 	}
 }
@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+type ClickEvent struct {
+	ent.Schema
+}
+
+func (ClickEvent) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New).Unique().Immutable(),
+		field.Time("occurred_at").Default(time.Now).Immutable(),
+		field.String("referrer_host").Default("").Immutable(),
+		field.String("ua_family").Default("").Immutable(),
+		field.String("visitor_hash").Immutable(),
+		field.String("country").Default("").Immutable(),
+	}
+}
+
+func (ClickEvent) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("bookmark", Bookmark.Type).
+			Ref("clicks").
+			Unique().
+			Required().
+			Immutable(),
+	}
+}
+
+func (ClickEvent) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("occurred_at").
+			Edges("bookmark"),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 	"github.com/google/uuid"
@@ -18,6 +19,19 @@ func (User) Fields() []ent.Field {
 		field.UUID("id", uuid.UUID{}).Default(uuid.New).Unique().Immutable(),
 		field.String("email").Unique(),
 		field.String("password_hash"),
+		// Consecutive failed logins, reset on success and forgotten once
+		// they are older than the lockout duration
+		field.Int("failed_login_attempts").Default(0),
+		field.Time("last_failed_login_at").Optional().Nillable(),
+		field.Time("locked_until").Optional().Nillable(),
+		// Set once the user follows the link mailed at registration
+		field.Time("email_verified_at").Optional().Nillable(),
+		// The TOTP secret, encrypted. It is pending until totp_enabled_at
+		// is set by confirming a first code. totp_last_step is the time
+		// step of the last accepted code, which can't be used again.
+		field.String("totp_secret").Optional().Nillable().Sensitive(),
+		field.Time("totp_enabled_at").Optional().Nillable(),
+		field.Int64("totp_last_step").Default(0),
 		field.Time("created_at").Default(time.Now),
 		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
 	}
@@ -26,5 +40,10 @@ func (User) Fields() []ent.Field {
 func (User) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("bookmarks", Bookmark.Type),
+		edge.To("refresh_tokens", RefreshToken.Type),
+		edge.To("password_reset_tokens", PasswordResetToken.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("recovery_codes", RecoveryCode.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
 	}
 }
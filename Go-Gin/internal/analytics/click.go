@@ -0,0 +1,135 @@
+package analytics
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"bookmark-shortener/ent"
+
+	"github.com/google/uuid"
+)
+
+// Click is a single redirect, reduced to the fields kept for analytics.
+// Raw IP addresses and user agents are never stored.
+type Click struct {
+	BookmarkID   uuid.UUID
+	OccurredAt   time.Time
+	ReferrerHost string
+	UAFamily     string
+	VisitorHash  string
+	Country      string
+}
+
+// Recorder turns redirect requests into ClickEvent rows.
+type Recorder struct {
+	client *ent.Client
+	geo    GeoIP
+	salt   []byte
+}
+
+func NewRecorder(client *ent.Client, geo GeoIP, salt string) *Recorder {
+	if geo == nil {
+		geo = NoGeoIP{}
+	}
+	return &Recorder{
+		client: client,
+		geo:    geo,
+		salt:   []byte(salt),
+	}
+}
+
+// NewClick extracts the analytics fields from a request.
+func (r *Recorder) NewClick(bookmarkID uuid.UUID, clientIP, userAgent, referrer string) Click {
+	country := ""
+	if addr, err := netip.ParseAddr(clientIP); err == nil {
+		country = r.geo.Country(addr)
+	}
+
+	return Click{
+		BookmarkID:   bookmarkID,
+		OccurredAt:   time.Now(),
+		ReferrerHost: ReferrerHost(referrer),
+		UAFamily:     UAFamily(userAgent),
+		VisitorHash:  r.VisitorHash(clientIP, userAgent),
+		Country:      country,
+	}
+}
+
+// Record stores a batch of clicks in a single statement.
+func (r *Recorder) Record(ctx context.Context, clicks []Click) error {
+	builders := make([]*ent.ClickEventCreate, len(clicks))
+	for i, click := range clicks {
+		builders[i] = r.client.ClickEvent.Create().
+			SetBookmarkID(click.BookmarkID).
+			SetOccurredAt(click.OccurredAt).
+			SetReferrerHost(click.ReferrerHost).
+			SetUaFamily(click.UAFamily).
+			SetVisitorHash(click.VisitorHash).
+			SetCountry(click.Country)
+	}
+	return r.client.ClickEvent.CreateBulk(builders...).Exec(ctx)
+}
+
+// VisitorHash fingerprints a visitor without storing their IP address. The
+// salt keeps the hash from being reversed by enumerating the IPv4 space.
+func (r *Recorder) VisitorHash(clientIP, userAgent string) string {
+	mac := hmac.New(sha256.New, r.salt)
+	mac.Write([]byte(clientIP))
+	mac.Write([]byte{0})
+	mac.Write([]byte(userAgent))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// ReferrerHost reduces a Referer header to its lowercased host name.
+func ReferrerHost(referrer string) string {
+	if referrer == "" {
+		return ""
+	}
+	u, err := url.Parse(referrer)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// uaFamilies is checked in order; several browsers include the tokens of
+// the ones they are derived from ("Chrome" claims "Safari", "Edge" claims
+// "Chrome").
+var uaFamilies = []struct {
+	token  string
+	family string
+}{
+	{"bot", "Bot"},
+	{"crawler", "Bot"},
+	{"spider", "Bot"},
+	{"curl/", "curl"},
+	{"wget/", "Wget"},
+	{"edg/", "Edge"},
+	{"opr/", "Opera"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"safari/", "Safari"},
+}
+
+// UAFamily maps a User-Agent header to a coarse browser family.
+func UAFamily(userAgent string) string {
+	if userAgent == "" {
+		return ""
+	}
+	ua := strings.ToLower(userAgent)
+	for _, f := range uaFamilies {
+		if strings.Contains(ua, f.token) {
+			return f.family
+		}
+	}
+	return "Other"
+}
@@ -0,0 +1,107 @@
+package analytics
+
+import (
+	"bufio"
+	"fmt"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// GeoIP resolves a client address to an ISO 3166-1 alpha-2 country code.
+// Implementations return "" when the country is unknown.
+type GeoIP interface {
+	Country(addr netip.Addr) string
+}
+
+// NoGeoIP is used when no GeoIP database is configured.
+type NoGeoIP struct{}
+
+func (NoGeoIP) Country(netip.Addr) string { return "" }
+
+type ipRange struct {
+	start   netip.Addr
+	end     netip.Addr
+	country string
+}
+
+// FileGeoIP is an in-memory GeoIP database loaded from a CSV file with one
+// "network,country" pair per line, where network is a CIDR prefix
+// ("81.2.69.0/24,GB"). Blank lines and lines starting with '#' are skipped.
+// Networks must not overlap, which holds for the usual country block exports.
+type FileGeoIP struct {
+	ranges []ipRange
+}
+
+func LoadFileGeoIP(path string) (*FileGeoIP, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var ranges []ipRange
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		network, country, ok := strings.Cut(line, ",")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected network,country", path, lineNo)
+		}
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(network))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		// Lookups unmap addresses, so IPv4-mapped networks are stored as IPv4
+		if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+			prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+		}
+		prefix = prefix.Masked()
+
+		ranges = append(ranges, ipRange{
+			start:   prefix.Addr(),
+			end:     lastAddr(prefix),
+			country: strings.ToUpper(strings.TrimSpace(country)),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start.Less(ranges[j].start)
+	})
+	return &FileGeoIP{ranges: ranges}, nil
+}
+
+func (g *FileGeoIP) Country(addr netip.Addr) string {
+	addr = addr.Unmap()
+	// Find the last range starting at or before addr
+	i := sort.Search(len(g.ranges), func(i int) bool {
+		return addr.Less(g.ranges[i].start)
+	}) - 1
+	if i < 0 {
+		return ""
+	}
+	r := g.ranges[i]
+	if r.start.BitLen() != addr.BitLen() || r.end.Less(addr) {
+		return ""
+	}
+	return r.country
+}
+
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Addr().AsSlice()
+	for bit := prefix.Bits(); bit < len(b)*8; bit++ {
+		b[bit/8] |= 1 << (7 - bit%8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
@@ -0,0 +1,129 @@
+package analytics
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeDB(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "geoip.csv")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileGeoIPCountry(t *testing.T) {
+	path := writeDB(t, `# network,country
+81.2.69.0/24,gb
+10.0.0.0/8, US
+
+  2001:db8::/32 , de
+::ffff:192.0.2.0/120,FR
+203.0.113.7/32,AU
+`)
+	g, err := LoadFileGeoIP(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"81.2.69.0", "GB"},
+		{"81.2.69.255", "GB"},
+		{"81.2.68.255", ""},
+		{"81.2.70.0", ""},
+		{"10.0.0.0", "US"},
+		{"10.255.255.255", "US"},
+		{"11.0.0.0", ""},
+		{"203.0.113.7", "AU"},
+		{"203.0.113.8", ""},
+		{"0.0.0.0", ""},
+		{"255.255.255.255", ""},
+		{"2001:db8::", "DE"},
+		{"2001:db8:ffff:ffff:ffff:ffff:ffff:ffff", "DE"},
+		{"2001:db9::", ""},
+		{"2001:db7:ffff::", ""},
+		{"::", ""},
+		// IPv4-mapped addresses are looked up as IPv4
+		{"::ffff:81.2.69.1", "GB"},
+		{"::ffff:11.0.0.1", ""},
+		// and IPv4-mapped networks are stored as IPv4
+		{"192.0.2.200", "FR"},
+		{"::ffff:192.0.2.1", "FR"},
+		// An IPv6 address sorting after every IPv4 range matches none
+		{"::1", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := g.Country(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Fatalf("Country(%s) = %q, want %q", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileGeoIPEmpty(t *testing.T) {
+	g, err := LoadFileGeoIP(writeDB(t, "# nothing here\n\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Country(netip.MustParseAddr("81.2.69.1")); got != "" {
+		t.Fatalf("Country = %q from an empty database", got)
+	}
+}
+
+func TestLoadFileGeoIPErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"missing country", "81.2.69.0/24,GB\n# ok\n10.0.0.0/8\n", ":3: expected network,country"},
+		{"bare address", "81.2.69.1,GB\n", ":1: netip.ParsePrefix"},
+		{"short address", "10.0.0/8,US\n", ":1: netip.ParsePrefix"},
+		{"prefix too long", "10.0.0.0/33,US\n", ":1: netip.ParsePrefix"},
+		{"ipv6 prefix too long", "2001:db8::/129,DE\n", ":1: netip.ParsePrefix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFileGeoIP(writeDB(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("LoadFileGeoIP = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := LoadFileGeoIP(filepath.Join(t.TempDir(), "missing.csv")); !os.IsNotExist(err) {
+		t.Fatalf("LoadFileGeoIP(missing) = %v, want a not-exist error", err)
+	}
+}
+
+func TestLastAddr(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{"10.0.0.0/8", "10.255.255.255"},
+		{"81.2.69.0/24", "81.2.69.255"},
+		{"81.2.69.128/25", "81.2.69.255"},
+		{"203.0.113.7/32", "203.0.113.7"},
+		{"0.0.0.0/0", "255.255.255.255"},
+		{"2001:db8::/32", "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff"},
+		{"2001:db8::/127", "2001:db8::1"},
+		{"2001:db8::1/128", "2001:db8::1"},
+		{"::/0", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"},
+	}
+	for _, tt := range tests {
+		if got := lastAddr(netip.MustParsePrefix(tt.prefix)); got != netip.MustParseAddr(tt.want) {
+			t.Errorf("lastAddr(%s) = %s, want %s", tt.prefix, got, tt.want)
+		}
+	}
+}
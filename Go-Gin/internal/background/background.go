@@ -0,0 +1,48 @@
+// Package background runs work that a request starts but does not wait for,
+// so the work neither delays the response nor shows in its timing.
+package background
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// taskTimeout bounds each task.
+const taskTimeout = 30 * time.Second
+
+// Group tracks running tasks, so shutdown can wait for them.
+type Group struct {
+	pending sync.WaitGroup
+}
+
+func NewGroup() *Group {
+	return &Group{}
+}
+
+// Go runs fn in the background. Its context carries the values of ctx,
+// such as the request logger, but ctx being canceled does not stop it.
+func (g *Group) Go(ctx context.Context, fn func(ctx context.Context)) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), taskTimeout)
+	g.pending.Add(1)
+	go func() {
+		defer g.pending.Done()
+		defer cancel()
+		fn(ctx)
+	}()
+}
+
+// Wait blocks until running tasks are done or ctx ends.
+func (g *Group) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
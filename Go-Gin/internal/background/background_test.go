@@ -0,0 +1,40 @@
+package background
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type ctxKey struct{}
+
+func TestGroupWait(t *testing.T) {
+	g := NewGroup()
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "request"))
+	release := make(chan struct{})
+	var value atomic.Value
+	g.Go(ctx, func(ctx context.Context) {
+		<-release
+		// The task outlives the request it was started from
+		if ctx.Err() == nil {
+			value.Store(ctx.Value(ctxKey{}))
+		}
+	})
+	cancel()
+
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	if err := g.Wait(short); err == nil {
+		t.Fatal("Wait returned while a task was running")
+	}
+
+	close(release)
+	if err := g.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if value.Load() != "request" {
+		t.Fatalf("task saw value %v", value.Load())
+	}
+}
@@ -0,0 +1,164 @@
+// Package baseurl decides the scheme and host of absolute links the server
+// hands out, such as short_url.
+package baseurl
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+// Resolver returns the configured base URL, or, for requests that arrive
+// from a trusted proxy, the URL the client used as reported by the proxy's
+// Forwarded or X-Forwarded-Proto/X-Forwarded-Host headers. Without trusted
+// proxies the request's Host header is never used, so clients cannot
+// change the links they are given. The path of the configured base URL is
+// kept either way.
+type Resolver struct {
+	configured string
+	path       string
+	proxies    []netip.Prefix
+}
+
+// New returns a resolver for baseURL. trustedProxies lists the IPs or
+// CIDRs of proxies whose forwarding headers are believed.
+func New(baseURL string, trustedProxies []string) (*Resolver, error) {
+	proxies, err := ParseProxies(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	configured := strings.TrimSuffix(baseURL, "/")
+	u, err := url.Parse(configured)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q", baseURL)
+	}
+	return &Resolver{configured: configured, path: u.Path, proxies: proxies}, nil
+}
+
+// ParseProxies parses IPs and CIDRs in the format accepted by Gin's
+// SetTrustedProxies.
+func ParseProxies(list []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(list))
+	for _, entry := range list {
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// Resolve returns the base URL for req, without a trailing slash.
+func (r *Resolver) Resolve(req *http.Request) string {
+	if !r.fromTrustedProxy(req) {
+		return r.configured
+	}
+
+	proto, host := forwarded(req.Header)
+	if proto == "" {
+		proto = lastValue(req.Header, "X-Forwarded-Proto")
+	}
+	if host == "" {
+		host = lastValue(req.Header, "X-Forwarded-Host")
+	}
+	if host == "" {
+		host = req.Host
+	}
+	if proto == "" {
+		proto = "http"
+		if req.TLS != nil {
+			proto = "https"
+		}
+	}
+
+	proto = strings.ToLower(proto)
+	if (proto != "http" && proto != "https") || !validHost(host) {
+		return r.configured
+	}
+	return proto + "://" + host + r.path
+}
+
+func (r *Resolver) fromTrustedProxy(req *http.Request) bool {
+	if len(r.proxies) == 0 {
+		return false
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range r.proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwarded reads proto and host from the last element of an RFC 7239
+// Forwarded header, which the trusted proxy added. Earlier elements come
+// from further upstream and may have been sent by the client.
+func forwarded(h http.Header) (proto, host string) {
+	value := lastValue(h, "Forwarded")
+	for _, pair := range strings.Split(value, ";") {
+		key, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		v = strings.Trim(v, `"`)
+		switch strings.ToLower(key) {
+		case "proto":
+			proto = v
+		case "host":
+			host = v
+		}
+	}
+	return proto, host
+}
+
+// lastValue returns the last element of a comma-separated header, which may
+// be split over several lines.
+func lastValue(h http.Header, name string) string {
+	values := h.Values(name)
+	if len(values) == 0 {
+		return ""
+	}
+	last := values[len(values)-1]
+	if i := strings.LastIndex(last, ","); i >= 0 {
+		last = last[i+1:]
+	}
+	return strings.TrimSpace(last)
+}
+
+// validHost accepts a host name or IP literal with an optional port, so a
+// forwarded value cannot inject a path or credentials into links.
+func validHost(host string) bool {
+	if host == "" {
+		return false
+	}
+	for _, ch := range host {
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		case ch == '.', ch == '-', ch == ':', ch == '[', ch == ']':
+		default:
+			return false
+		}
+	}
+	return true
+}
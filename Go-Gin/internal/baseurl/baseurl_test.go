@@ -0,0 +1,76 @@
+package baseurl
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name       string
+		proxies    []string
+		remoteAddr string
+		tls        bool
+		headers    map[string]string
+		want       string
+	}{
+		{"no proxies", nil, "10.0.0.1:1234", false, map[string]string{"X-Forwarded-Host": "evil.example"}, "https://sho.rt/s"},
+		{"untrusted peer", []string{"10.0.0.0/8"}, "192.0.2.1:1234", false, map[string]string{"X-Forwarded-Host": "evil.example"}, "https://sho.rt/s"},
+		{"x-forwarded", []string{"10.0.0.0/8"}, "10.1.2.3:1234", false, map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "evil.example, links.example"}, "https://links.example/s"},
+		{"forwarded wins", []string{"10.0.0.1"}, "10.0.0.1:1234", false, map[string]string{"Forwarded": `proto=http;host="evil.example", proto=https;host="a.example"`, "X-Forwarded-Proto": "http"}, "https://a.example/s"},
+		{"host header", []string{"10.0.0.1"}, "10.0.0.1:1234", false, nil, "http://example.com/s"},
+		{"tls without proto", []string{"10.0.0.1"}, "10.0.0.1:1234", true, nil, "https://example.com/s"},
+		{"ipv6 proxy", []string{"::1"}, "[::1]:1234", false, map[string]string{"X-Forwarded-Host": "[2001:db8::1]:8443"}, "http://[2001:db8::1]:8443/s"},
+		{"invalid proto", []string{"10.0.0.1"}, "10.0.0.1:1234", false, map[string]string{"X-Forwarded-Proto": "javascript"}, "https://sho.rt/s"},
+		{"invalid host", []string{"10.0.0.1"}, "10.0.0.1:1234", false, map[string]string{"X-Forwarded-Host": "evil.example/path"}, "https://sho.rt/s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New("https://sho.rt/s/", tt.proxies)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest("GET", "/bookmarks/get", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			if got := r.Resolve(req); got != tt.want {
+				t.Fatalf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveRepeatedHeaders(t *testing.T) {
+	r, err := New("https://sho.rt", []string{"10.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("GET", "/bookmarks/get", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	// The client's own header line comes before the one the proxy appended
+	req.Header.Add("X-Forwarded-Host", "evil.example")
+	req.Header.Add("X-Forwarded-Host", "links.example")
+	req.Header.Add("X-Forwarded-Proto", "http, https")
+
+	if got, want := r.Resolve(req), "https://links.example"; got != want {
+		t.Fatalf("Resolve() = %q, want %q", got, want)
+	}
+}
+
+func TestParseProxies(t *testing.T) {
+	if _, err := ParseProxies([]string{"10.0.0.0/8", "192.0.2.1", "::1", "fd00::/8"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, invalid := range []string{"10.0.0.0/33", "proxy.internal", "10.0.0"} {
+		if _, err := ParseProxies([]string{invalid}); err == nil {
+			t.Errorf("ParseProxies(%q) succeeded", invalid)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+// Package bench drives load against a bookmark API backend and measures
+// latency with HDR histograms, so the Gin, Express and FastAPI servers can
+// be compared under the same traffic.
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Op is a kind of request in the traffic mix.
+type Op string
+
+const (
+	OpRedirect Op = "redirect"
+	OpList     Op = "list"
+	OpCreate   Op = "create"
+)
+
+// Latencies are recorded in microseconds up to a minute.
+const (
+	highestLatency = int64(time.Minute / time.Microsecond)
+	sigFigs        = 3
+)
+
+// MixEntry is one kind of request and its relative weight.
+type MixEntry struct {
+	Op     Op
+	Weight int
+}
+
+// Mix is the weighted set of requests to send.
+type Mix []MixEntry
+
+// ParseMix parses a mix such as "redirect=80,list=15,create=5".
+func ParseMix(s string) (Mix, error) {
+	var mix Mix
+	seen := make(map[Op]bool)
+	for _, part := range strings.Split(s, ",") {
+		name, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q, expected op=weight", part)
+		}
+		op := Op(name)
+		switch op {
+		case OpRedirect, OpList, OpCreate:
+		default:
+			return nil, fmt.Errorf("unknown op %q, expected redirect, list or create", name)
+		}
+		if seen[op] {
+			return nil, fmt.Errorf("op %q listed twice", name)
+		}
+		seen[op] = true
+
+		w, err := strconv.Atoi(weight)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("invalid weight %q for %s", weight, name)
+		}
+		if w > 0 {
+			mix = append(mix, MixEntry{Op: op, Weight: w})
+		}
+	}
+	if len(mix) == 0 {
+		return nil, errors.New("mix has no ops with a positive weight")
+	}
+	return mix, nil
+}
+
+func (m Mix) String() string {
+	parts := make([]string, len(m))
+	for i, e := range m {
+		parts[i] = fmt.Sprintf("%s=%d", e.Op, e.Weight)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (m Mix) pick(rng *rand.Rand) Op {
+	total := 0
+	for _, e := range m {
+		total += e.Weight
+	}
+	n := rng.IntN(total)
+	for _, e := range m {
+		if n < e.Weight {
+			return e.Op
+		}
+		n -= e.Weight
+	}
+	return m[len(m)-1].Op
+}
+
+// Config controls a run.
+type Config struct {
+	BaseURL string
+	// RunID keeps the URLs of created bookmarks unique across runs.
+	RunID string
+	Mix   Mix
+	// Concurrency is the number of workers. With RPS set it bounds the
+	// number of requests in flight.
+	Concurrency int
+	// RPS is the target request rate. Zero sends requests back to back on
+	// every worker instead.
+	RPS float64
+	// Requests sent during Warmup are not measured.
+	Warmup   time.Duration
+	Duration time.Duration
+}
+
+// Stats are the measurements for one op.
+type Stats struct {
+	Latency  *Histogram
+	Requests int64
+	// Errors counts failed requests by kind, e.g. "status 500" or
+	// "timeout".
+	Errors map[string]int64
+}
+
+func newStats() *Stats {
+	return &Stats{
+		Latency: NewHistogram(highestLatency, sigFigs),
+		Errors:  make(map[string]int64),
+	}
+}
+
+func (s *Stats) merge(other *Stats) {
+	s.Latency.Merge(other.Latency)
+	s.Requests += other.Requests
+	for kind, n := range other.Errors {
+		s.Errors[kind] += n
+	}
+}
+
+// ErrorCount returns the number of failed requests.
+func (s *Stats) ErrorCount() int64 {
+	var n int64
+	for _, c := range s.Errors {
+		n += c
+	}
+	return n
+}
+
+// Result holds the measurements of a run.
+type Result struct {
+	// Elapsed is the measured window, excluding warmup.
+	Elapsed time.Duration
+	Ops     map[Op]*Stats
+}
+
+// Total merges the stats of every op.
+func (r *Result) Total() *Stats {
+	total := newStats()
+	for _, s := range r.Ops {
+		total.merge(s)
+	}
+	return total
+}
+
+// Run sends the configured traffic against the seeded fixture. Latency is
+// measured from when a request was due to be sent, so requests delayed by
+// a saturated server count against it instead of silently lowering the
+// rate (coordinated omission).
+func Run(ctx context.Context, client *http.Client, cfg Config, fixture *Fixture) (*Result, error) {
+	if len(fixture.Tokens) == 0 {
+		return nil, errors.New("fixture has no users")
+	}
+	if len(fixture.ShortCodes) == 0 {
+		for _, e := range cfg.Mix {
+			if e.Op == OpRedirect {
+				return nil, errors.New("redirect traffic needs seeded bookmarks")
+			}
+		}
+	}
+	concurrency := max(cfg.Concurrency, 1)
+
+	start := time.Now()
+	measureFrom := start.Add(cfg.Warmup)
+	end := measureFrom.Add(cfg.Duration)
+
+	// Every job is the time a request is due; zero means as soon as a
+	// worker picks it up
+	jobs := make(chan time.Time, concurrency)
+	go func() {
+		defer close(jobs)
+		if cfg.RPS > 0 {
+			schedule(ctx, jobs, start, end, cfg.RPS)
+		} else {
+			for time.Now().Before(end) && ctx.Err() == nil {
+				jobs <- time.Time{}
+			}
+		}
+	}()
+
+	workers := make([]*worker, concurrency)
+	var wg sync.WaitGroup
+	for i := range workers {
+		w := &worker{
+			id:      i,
+			client:  client,
+			cfg:     cfg,
+			fixture: fixture,
+			rng:     rand.New(rand.NewPCG(uint64(start.UnixNano()), uint64(i))),
+			stats:   make(map[Op]*Stats),
+		}
+		workers[i] = w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for due := range jobs {
+				if due.IsZero() {
+					due = time.Now()
+				}
+				if due.After(end) {
+					continue
+				}
+				w.do(ctx, due, !due.Before(measureFrom))
+			}
+		}()
+	}
+	wg.Wait()
+
+	result := &Result{
+		Elapsed: min(time.Since(measureFrom), cfg.Duration),
+		Ops:     make(map[Op]*Stats),
+	}
+	for _, e := range cfg.Mix {
+		result.Ops[e.Op] = newStats()
+	}
+	for _, w := range workers {
+		for op, s := range w.stats {
+			result.Ops[op].merge(s)
+		}
+	}
+	return result, ctx.Err()
+}
+
+// schedule emits due times at a fixed rate. When the workers fall behind
+// the backlog is sent as soon as they free up, keeping the original due
+// times.
+func schedule(ctx context.Context, jobs chan<- time.Time, start, end time.Time, rps float64) {
+	interval := time.Duration(float64(time.Second) / rps)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for due := start; due.Before(end); due = due.Add(interval) {
+		if wait := time.Until(due); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case jobs <- due:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+type worker struct {
+	id      int
+	client  *http.Client
+	cfg     Config
+	fixture *Fixture
+	rng     *rand.Rand
+	stats   map[Op]*Stats
+	created int
+}
+
+func (w *worker) do(ctx context.Context, due time.Time, measure bool) {
+	op := w.cfg.Mix.pick(w.rng)
+	errKind := w.send(ctx, op)
+	if !measure {
+		return
+	}
+
+	s, ok := w.stats[op]
+	if !ok {
+		s = newStats()
+		w.stats[op] = s
+	}
+	s.Latency.Record(int64(time.Since(due) / time.Microsecond))
+	s.Requests++
+	if errKind != "" {
+		s.Errors[errKind]++
+	}
+}
+
+// send performs one request and returns the kind of error, if any.
+func (w *worker) send(ctx context.Context, op Op) string {
+	token := w.fixture.Tokens[w.rng.IntN(len(w.fixture.Tokens))]
+
+	var req *http.Request
+	var err error
+	var want int
+	switch op {
+	case OpRedirect:
+		code := w.fixture.ShortCodes[w.rng.IntN(len(w.fixture.ShortCodes))]
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, w.cfg.BaseURL+"/"+code, nil)
+		want = http.StatusFound
+	case OpList:
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, w.cfg.BaseURL+"/bookmarks/get", nil)
+		want = http.StatusOK
+	case OpCreate:
+		w.created++
+		body := fmt.Sprintf(`{"title":"Bench","url":"https://example.com/bench/%s/w%d/%d"}`, w.cfg.RunID, w.id, w.created)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.BaseURL+"/bookmarks/create", strings.NewReader(body))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		want = http.StatusCreated
+	}
+	if err != nil {
+		return "request"
+	}
+	if op != OpRedirect {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || isTimeout(err) {
+			return "timeout"
+		}
+		return "transport"
+	}
+	// Drain the body so the connection is reused
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != want {
+		return "status " + strconv.Itoa(resp.StatusCode)
+	}
+	return ""
+}
+
+func isTimeout(err error) bool {
+	var t interface{ Timeout() bool }
+	return errors.As(err, &t) && t.Timeout()
+}
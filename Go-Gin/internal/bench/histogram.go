@@ -0,0 +1,182 @@
+package bench
+
+import (
+	"math"
+	"math/bits"
+)
+
+// Histogram is a High Dynamic Range histogram: it records integer values
+// between 1 and a fixed maximum with a relative error bounded by the chosen
+// number of significant decimal digits, in constant memory. Values are
+// grouped into buckets whose width doubles from one bucket to the next, and
+// each bucket is split into the same number of linear sub-buckets.
+//
+// This follows the layout of the reference HdrHistogram implementation with
+// a lowest trackable value of 1. It is not safe for concurrent use; record
+// per worker and Merge.
+type Histogram struct {
+	highest int64
+
+	subBucketHalfCountMagnitude int
+	subBucketHalfCount          int
+	subBucketMask               int64
+	subBucketCount              int
+	bucketCount                 int
+
+	counts     []int64
+	total      int64
+	sum        float64
+	min        int64
+	max        int64
+	overflowed int64
+}
+
+// NewHistogram tracks values from 1 to highest with sigFigs (1-5)
+// significant digits of precision.
+func NewHistogram(highest int64, sigFigs int) *Histogram {
+	if sigFigs < 1 || sigFigs > 5 {
+		panic("bench: significant figures must be between 1 and 5")
+	}
+	if highest < 2 {
+		panic("bench: highest trackable value must be at least 2")
+	}
+
+	largestWithSingleUnitResolution := 2 * int64(math.Pow10(sigFigs))
+	subBucketCountMagnitude := bits.Len64(uint64(largestWithSingleUnitResolution - 1))
+	subBucketHalfCountMagnitude := max(subBucketCountMagnitude, 1) - 1
+	subBucketCount := 1 << (subBucketHalfCountMagnitude + 1)
+
+	// Each additional bucket doubles the trackable range
+	bucketCount := 1
+	for smallestUntrackable := int64(subBucketCount); smallestUntrackable <= highest; smallestUntrackable <<= 1 {
+		bucketCount++
+		if smallestUntrackable > math.MaxInt64/2 {
+			break
+		}
+	}
+
+	return &Histogram{
+		highest:                     highest,
+		subBucketHalfCountMagnitude: subBucketHalfCountMagnitude,
+		subBucketHalfCount:          subBucketCount / 2,
+		subBucketMask:               int64(subBucketCount - 1),
+		subBucketCount:              subBucketCount,
+		bucketCount:                 bucketCount,
+		counts:                      make([]int64, (bucketCount+1)*(subBucketCount/2)),
+		min:                         math.MaxInt64,
+	}
+}
+
+// Record adds a value. Values below 1 are recorded as 1 and values above
+// the highest trackable value as that value; Overflowed counts the latter.
+func (h *Histogram) Record(v int64) {
+	if v < 1 {
+		v = 1
+	}
+	if v > h.highest {
+		v = h.highest
+		h.overflowed++
+	}
+	h.counts[h.countsIndex(v)]++
+	h.total++
+	h.sum += float64(v)
+	h.min = min(h.min, v)
+	h.max = max(h.max, v)
+}
+
+// Merge adds every value recorded in other, which must have been created
+// with the same parameters.
+func (h *Histogram) Merge(other *Histogram) {
+	if len(other.counts) != len(h.counts) {
+		panic("bench: merging histograms with different layouts")
+	}
+	for i, c := range other.counts {
+		h.counts[i] += c
+	}
+	h.total += other.total
+	h.sum += other.sum
+	h.overflowed += other.overflowed
+	if other.total > 0 {
+		h.min = min(h.min, other.min)
+		h.max = max(h.max, other.max)
+	}
+}
+
+// Count returns the number of recorded values.
+func (h *Histogram) Count() int64 { return h.total }
+
+// Overflowed returns how many values exceeded the trackable range.
+func (h *Histogram) Overflowed() int64 { return h.overflowed }
+
+// Min returns the smallest recorded value, exactly.
+func (h *Histogram) Min() int64 {
+	if h.total == 0 {
+		return 0
+	}
+	return h.min
+}
+
+// Max returns the largest recorded value, exactly.
+func (h *Histogram) Max() int64 { return h.max }
+
+// Mean returns the exact mean of the recorded values.
+func (h *Histogram) Mean() float64 {
+	if h.total == 0 {
+		return 0
+	}
+	return h.sum / float64(h.total)
+}
+
+// ValueAtQuantile returns the value below which q percent (0-100) of the
+// recorded values fall, within the histogram's precision.
+func (h *Histogram) ValueAtQuantile(q float64) int64 {
+	if h.total == 0 {
+		return 0
+	}
+	q = min(max(q, 0), 100)
+	target := max(int64(q/100*float64(h.total)+0.5), 1)
+
+	var seen int64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= target {
+			// Never report more than was actually recorded
+			return min(h.highestEquivalentValue(h.valueFromIndex(i)), h.max)
+		}
+	}
+	return h.max
+}
+
+func (h *Histogram) bucketIndex(v int64) int {
+	// The position of the highest set bit above the first bucket's range
+	return bits.Len64(uint64(v|h.subBucketMask)) - (h.subBucketHalfCountMagnitude + 1)
+}
+
+func (h *Histogram) countsIndex(v int64) int {
+	bucket := h.bucketIndex(v)
+	subBucket := int(v >> bucket)
+	// Bucket 0 uses all sub-buckets; later buckets only their upper half,
+	// since the lower half is covered by the previous bucket
+	return (bucket+1)<<h.subBucketHalfCountMagnitude + subBucket - h.subBucketHalfCount
+}
+
+func (h *Histogram) valueFromIndex(i int) int64 {
+	bucket := (i >> h.subBucketHalfCountMagnitude) - 1
+	subBucket := i&(h.subBucketHalfCount-1) + h.subBucketHalfCount
+	if bucket < 0 {
+		subBucket -= h.subBucketHalfCount
+		bucket = 0
+	}
+	return int64(subBucket) << bucket
+}
+
+// highestEquivalentValue returns the largest value that shares v's
+// sub-bucket.
+func (h *Histogram) highestEquivalentValue(v int64) int64 {
+	bucket := h.bucketIndex(v)
+	if int(v>>bucket) >= h.subBucketCount {
+		bucket++
+	}
+	lowest := v >> bucket << bucket
+	return lowest + 1<<bucket - 1
+}
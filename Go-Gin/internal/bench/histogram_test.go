@@ -0,0 +1,75 @@
+package bench
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestHistogramQuantilesWithinPrecision(t *testing.T) {
+	h := NewHistogram(3_600_000_000, 3)
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	values := make([]int64, 100_000)
+	for i := range values {
+		// Latency-like: mostly small with a long tail
+		values[i] = int64(math.Exp(rng.Float64()*16)) + 1
+		h.Record(values[i])
+	}
+	slices.Sort(values)
+
+	for _, q := range []float64{0, 50, 90, 99, 99.9, 100} {
+		idx := max(int(q/100*float64(len(values))+0.5)-1, 0)
+		want := values[idx]
+		got := h.ValueAtQuantile(q)
+		if rel := math.Abs(float64(got-want)) / float64(want); rel > 0.001 {
+			t.Errorf("p%v = %d, want %d (relative error %.5f)", q, got, want, rel)
+		}
+	}
+
+	if h.Count() != int64(len(values)) {
+		t.Fatalf("Count = %d", h.Count())
+	}
+	if h.Min() != values[0] || h.Max() != values[len(values)-1] {
+		t.Fatalf("Min/Max = %d/%d, want %d/%d", h.Min(), h.Max(), values[0], values[len(values)-1])
+	}
+}
+
+func TestHistogramExactForSmallValues(t *testing.T) {
+	h := NewHistogram(1000, 3)
+	for v := int64(1); v <= 1000; v++ {
+		h.Record(v)
+	}
+	for _, tc := range []struct {
+		q    float64
+		want int64
+	}{{50, 500}, {90, 900}, {99, 990}, {100, 1000}} {
+		if got := h.ValueAtQuantile(tc.q); got != tc.want {
+			t.Errorf("p%v = %d, want %d", tc.q, got, tc.want)
+		}
+	}
+	if h.Mean() != 500.5 {
+		t.Errorf("Mean = %v", h.Mean())
+	}
+}
+
+func TestHistogramClampsAndMerges(t *testing.T) {
+	a := NewHistogram(10_000, 2)
+	b := NewHistogram(10_000, 2)
+
+	a.Record(0)
+	a.Record(50)
+	b.Record(20_000)
+
+	a.Merge(b)
+	if a.Count() != 3 || a.Overflowed() != 1 {
+		t.Fatalf("Count/Overflowed = %d/%d", a.Count(), a.Overflowed())
+	}
+	if a.Min() != 1 || a.Max() != 10_000 {
+		t.Fatalf("Min/Max = %d/%d", a.Min(), a.Max())
+	}
+	if got := a.ValueAtQuantile(100); got != 10_000 {
+		t.Fatalf("p100 = %d", got)
+	}
+}
@@ -0,0 +1,156 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Report is the serialisable summary of a run. Latencies are in
+// milliseconds.
+type Report struct {
+	Name       string       `json:"name"`
+	Target     string       `json:"target"`
+	StartedAt  time.Time    `json:"started_at"`
+	Config     ReportConfig `json:"config"`
+	Elapsed    float64      `json:"elapsed_seconds"`
+	Total      OpReport     `json:"total"`
+	Operations []OpReport   `json:"operations"`
+}
+
+type ReportConfig struct {
+	Mix              string  `json:"mix"`
+	Concurrency      int     `json:"concurrency"`
+	RPS              float64 `json:"rps,omitempty"`
+	Duration         float64 `json:"duration_seconds"`
+	Warmup           float64 `json:"warmup_seconds"`
+	Users            int     `json:"users"`
+	BookmarksPerUser int     `json:"bookmarks_per_user"`
+}
+
+type OpReport struct {
+	Op         string           `json:"op"`
+	Requests   int64            `json:"requests"`
+	Throughput float64          `json:"throughput_rps"`
+	Errors     int64            `json:"errors"`
+	ErrorRate  float64          `json:"error_rate"`
+	ErrorKinds map[string]int64 `json:"error_kinds,omitempty"`
+	Latency    LatencyReport    `json:"latency_ms"`
+}
+
+type LatencyReport struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	P999 float64 `json:"p99_9"`
+	Max  float64 `json:"max"`
+}
+
+// NewReport summarises result. Operations are listed in mix order.
+func NewReport(name string, cfg Config, users, bookmarksPerUser int, startedAt time.Time, result *Result) *Report {
+	r := &Report{
+		Name:      name,
+		Target:    cfg.BaseURL,
+		StartedAt: startedAt.UTC(),
+		Config: ReportConfig{
+			Mix:              cfg.Mix.String(),
+			Concurrency:      cfg.Concurrency,
+			RPS:              cfg.RPS,
+			Duration:         cfg.Duration.Seconds(),
+			Warmup:           cfg.Warmup.Seconds(),
+			Users:            users,
+			BookmarksPerUser: bookmarksPerUser,
+		},
+		Elapsed: result.Elapsed.Seconds(),
+		Total:   opReport("total", result.Total(), result.Elapsed),
+	}
+	for _, e := range cfg.Mix {
+		r.Operations = append(r.Operations, opReport(string(e.Op), result.Ops[e.Op], result.Elapsed))
+	}
+	return r
+}
+
+func opReport(op string, s *Stats, elapsed time.Duration) OpReport {
+	r := OpReport{
+		Op:       op,
+		Requests: s.Requests,
+		Errors:   s.ErrorCount(),
+		Latency: LatencyReport{
+			Min:  millis(s.Latency.Min()),
+			Mean: s.Latency.Mean() / 1000,
+			P50:  millis(s.Latency.ValueAtQuantile(50)),
+			P90:  millis(s.Latency.ValueAtQuantile(90)),
+			P95:  millis(s.Latency.ValueAtQuantile(95)),
+			P99:  millis(s.Latency.ValueAtQuantile(99)),
+			P999: millis(s.Latency.ValueAtQuantile(99.9)),
+			Max:  millis(s.Latency.Max()),
+		},
+	}
+	if elapsed > 0 {
+		r.Throughput = float64(s.Requests) / elapsed.Seconds()
+	}
+	if s.Requests > 0 {
+		r.ErrorRate = float64(r.Errors) / float64(s.Requests)
+	}
+	if len(s.Errors) > 0 {
+		r.ErrorKinds = maps.Clone(s.Errors)
+	}
+	return r
+}
+
+func millis(us int64) float64 {
+	return float64(us) / 1000
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes the report as a markdown section with one table row
+// per operation.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", r.Name)
+	fmt.Fprintf(&b, "- Target: %s\n", r.Target)
+	fmt.Fprintf(&b, "- Started: %s\n", r.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Mix: %s\n", r.Config.Mix)
+	if r.Config.RPS > 0 {
+		fmt.Fprintf(&b, "- Load: %g req/s, up to %d in flight\n", r.Config.RPS, r.Config.Concurrency)
+	} else {
+		fmt.Fprintf(&b, "- Load: %d concurrent workers\n", r.Config.Concurrency)
+	}
+	fmt.Fprintf(&b, "- Duration: %gs after %gs warmup\n", r.Config.Duration, r.Config.Warmup)
+	fmt.Fprintf(&b, "- Fixture: %d users, %d bookmarks each\n\n", r.Config.Users, r.Config.BookmarksPerUser)
+
+	b.WriteString("| Operation | Requests | Throughput (req/s) | Error rate | p50 (ms) | p90 (ms) | p99 (ms) | p99.9 (ms) | Max (ms) |\n")
+	b.WriteString("|---|---:|---:|---:|---:|---:|---:|---:|---:|\n")
+	for _, op := range append(slices.Clone(r.Operations), r.Total) {
+		name := op.Op
+		if op.Op == "total" {
+			name = "**total**"
+		}
+		fmt.Fprintf(&b, "| %s | %d | %.1f | %.2f%% | %.2f | %.2f | %.2f | %.2f | %.2f |\n",
+			name, op.Requests, op.Throughput, op.ErrorRate*100,
+			op.Latency.P50, op.Latency.P90, op.Latency.P99, op.Latency.P999, op.Latency.Max)
+	}
+
+	if len(r.Total.ErrorKinds) > 0 {
+		b.WriteString("\nErrors:\n")
+		for _, kind := range slices.Sorted(maps.Keys(r.Total.ErrorKinds)) {
+			fmt.Fprintf(&b, "- %s: %d\n", kind, r.Total.ErrorKinds[kind])
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,104 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const seedPassword = "bench-password"
+
+// Fixture is the data created before a run: one access token per user and
+// the short codes of every seeded bookmark.
+type Fixture struct {
+	Tokens     []string
+	ShortCodes []string
+}
+
+// Seed registers users through the public API and creates
+// bookmarksPerUser bookmarks for each of them. runID keeps emails and URLs
+// unique across runs against the same database.
+func Seed(ctx context.Context, client *http.Client, baseURL, runID string, users, bookmarksPerUser, parallel int) (*Fixture, error) {
+	f := &Fixture{
+		Tokens:     make([]string, users),
+		ShortCodes: make([]string, users*bookmarksPerUser),
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(parallel, 1))
+	for i := range users {
+		g.Go(func() error {
+			creds := map[string]string{
+				"email":    fmt.Sprintf("bench-%s-%d@example.com", runID, i),
+				"password": seedPassword,
+			}
+			if err := postJSON(ctx, client, baseURL+"/auth/register", "", creds, http.StatusCreated, nil); err != nil {
+				return fmt.Errorf("register user %d: %w", i, err)
+			}
+			var tokens struct {
+				AccessToken string `json:"access_token"`
+			}
+			if err := postJSON(ctx, client, baseURL+"/auth/token", "", creds, http.StatusOK, &tokens); err != nil {
+				return fmt.Errorf("log in user %d: %w", i, err)
+			}
+			f.Tokens[i] = tokens.AccessToken
+
+			for j := range bookmarksPerUser {
+				body := map[string]string{
+					"title": fmt.Sprintf("Bench %d/%d", i, j),
+					"url":   fmt.Sprintf("https://example.com/bench/%s/%d/%d", runID, i, j),
+				}
+				var b struct {
+					ShortCode string `json:"short_code"`
+				}
+				if err := postJSON(ctx, client, baseURL+"/bookmarks/create", tokens.AccessToken, body, http.StatusCreated, &b); err != nil {
+					return fmt.Errorf("create bookmark %d for user %d: %w", j, i, err)
+				}
+				f.ShortCodes[i*bookmarksPerUser+j] = b.ShortCode
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, url, token string, body any, status int, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != status {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if out != nil {
+		return json.Unmarshal(respBody, out)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/ent/hook"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+)
+
+var ErrNotFound = errors.New("short code not found")
+
+const (
+	DefaultSize        = 10000
+	DefaultTTL         = 5 * time.Minute
+	DefaultNegativeTTL = 30 * time.Second
+)
+
+// Stats are cumulative counters since the cache was created.
+type Stats struct {
+	Hits         uint64 `json:"hits"`
+	NegativeHits uint64 `json:"negative_hits"`
+	Misses       uint64 `json:"misses"`
+	Size         int    `json:"size"`
+}
+
+// ShortCodeCache resolves short codes to bookmarks in front of the database.
+// Unknown codes are cached too (as nil) for a shorter TTL, so scanners
+// probing random codes don't reach Postgres. Concurrent misses for the same
+// code are collapsed into a single query.
+//
+// Cached bookmarks are shared between requests and must not be modified.
+// Their visit_count is only as fresh as the entry.
+type ShortCodeCache struct {
+	client      *ent.Client
+	lru         *LRU[string, *ent.Bookmark]
+	ttl         time.Duration
+	negativeTTL time.Duration
+	group       singleflight.Group
+
+	// mu orders Invalidate against queries storing their result, so a
+	// query that read a row before an invalidation can't cache it after.
+	mu       sync.Mutex
+	inFlight map[string]*flight
+
+	hits         atomic.Uint64
+	negativeHits atomic.Uint64
+	misses       atomic.Uint64
+}
+
+func NewShortCodeCache(client *ent.Client, size int, ttl, negativeTTL time.Duration) *ShortCodeCache {
+	return &ShortCodeCache{
+		client:      client,
+		lru:         NewLRU[string, *ent.Bookmark](size),
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		inFlight:    make(map[string]*flight),
+	}
+}
+
+// flight tracks the queries running for one short code. Invalidations bump
+// generation; a query only stores its result if the generation is unchanged
+// since it started.
+type flight struct {
+	generation uint64
+	queries    int
+}
+
+// Get returns the bookmark with the given short code, or ErrNotFound.
+func (c *ShortCodeCache) Get(ctx context.Context, shortCode string) (*ent.Bookmark, error) {
+	if b, ok := c.lru.Get(shortCode); ok {
+		if b == nil {
+			c.negativeHits.Add(1)
+			return nil, ErrNotFound
+		}
+		c.hits.Add(1)
+		return b, nil
+	}
+	c.misses.Add(1)
+
+	v, err, _ := c.group.Do(shortCode, func() (interface{}, error) {
+		generation := c.startQuery(shortCode)
+		// Detach from the first caller so its cancellation doesn't fail
+		// everyone waiting on the same code
+		b, err := c.client.Bookmark.Query().
+			Where(bookmark.ShortCode(shortCode)).
+			Only(context.WithoutCancel(ctx))
+		if err != nil {
+			if ent.IsNotFound(err) {
+				c.finishQuery(shortCode, generation, nil, c.negativeTTL)
+				return nil, ErrNotFound
+			}
+			c.finishQuery(shortCode, generation, nil, 0)
+			return nil, err
+		}
+		c.finishQuery(shortCode, generation, b, c.ttl)
+		return b, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*ent.Bookmark), nil
+}
+
+// startQuery registers a query for shortCode and returns the generation to
+// pass to finishQuery.
+func (c *ShortCodeCache) startQuery(shortCode string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f, ok := c.inFlight[shortCode]
+	if !ok {
+		f = &flight{}
+		c.inFlight[shortCode] = f
+	}
+	f.queries++
+	return f.generation
+}
+
+// finishQuery caches b for ttl unless shortCode was invalidated since the
+// query started. A zero ttl only unregisters the query.
+func (c *ShortCodeCache) finishQuery(shortCode string, generation uint64, b *ent.Bookmark, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f := c.inFlight[shortCode]
+	if ttl > 0 && f.generation == generation {
+		c.lru.Set(shortCode, b, ttl)
+	}
+	if f.queries--; f.queries == 0 {
+		delete(c.inFlight, shortCode)
+	}
+}
+
+func (c *ShortCodeCache) Stats() Stats {
+	return Stats{
+		Hits:         c.hits.Load(),
+		NegativeHits: c.negativeHits.Load(),
+		Misses:       c.misses.Load(),
+		Size:         c.lru.Len(),
+	}
+}
+
+// Invalidate drops the given short codes and any entries of the given
+// bookmark IDs. Queries already running for those codes don't cache their
+// result. The codes of the IDs aren't known, so invalidating IDs does that
+// for every running query.
+func (c *ShortCodeCache) Invalidate(shortCodes []string, ids []uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(ids) > 0 {
+		for _, f := range c.inFlight {
+			f.generation++
+		}
+	} else {
+		for _, code := range shortCodes {
+			if f, ok := c.inFlight[code]; ok {
+				f.generation++
+			}
+		}
+	}
+	for _, code := range shortCodes {
+		c.lru.Delete(code)
+		c.group.Forget(code)
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	stale := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		stale[id] = struct{}{}
+	}
+	c.lru.DeleteFunc(func(code string, b *ent.Bookmark) bool {
+		if b == nil {
+			return false
+		}
+		_, ok := stale[b.ID]
+		return ok
+	})
+}
+
+// Hook returns an Ent hook that keeps the cache consistent with writes made
+// through the client it is registered on. Register it with
+// client.Bookmark.Use.
+func (c *ShortCodeCache) Hook() ent.Hook {
+	return func(next ent.Mutator) ent.Mutator {
+		return hook.BookmarkFunc(func(ctx context.Context, m *ent.BookmarkMutation) (ent.Value, error) {
+			var codes []string
+			if code, ok := m.ShortCode(); ok {
+				// A new or renamed code may be cached as unknown
+				codes = append(codes, code)
+			}
+
+			var ids []uuid.UUID
+			if !m.Op().Is(ent.OpCreate) && !onlyCounters(m) {
+				var err error
+				if ids, err = m.IDs(ctx); err != nil {
+					return nil, err
+				}
+			}
+
+			c.Invalidate(codes, ids)
+			v, err := next.Mutate(ctx, m)
+			// Invalidate again in case a concurrent read re-cached the old
+			// row while the mutation was running
+			c.Invalidate(codes, ids)
+			return v, err
+		})
+	}
+}
+
+// onlyCounters reports whether a mutation only bumps visit counters, which
+// cached entries are allowed to be stale on.
+func onlyCounters(m *ent.BookmarkMutation) bool {
+	// Deletes set no fields but must drop the entry
+	if m.Op().Is(ent.OpDelete|ent.OpDeleteOne) || len(m.ClearedFields()) > 0 {
+		return false
+	}
+	for _, f := range m.Fields() {
+		if f != bookmark.FieldVisitCount && f != bookmark.FieldUpdatedAt {
+			return false
+		}
+	}
+	return true
+}
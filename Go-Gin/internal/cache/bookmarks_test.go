@@ -0,0 +1,249 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/enttest"
+	"bookmark-shortener/internal/database"
+)
+
+// gate blocks bookmark queries after they have read from the database,
+// while it is closed.
+type gate struct {
+	queries atomic.Int32
+	mu      sync.Mutex
+	closed  bool
+	read    chan struct{}
+	release chan struct{}
+}
+
+func (g *gate) close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.closed = true
+	g.read = make(chan struct{}, 100)
+	g.release = make(chan struct{})
+}
+
+func (g *gate) open() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		g.closed = false
+		close(g.release)
+	}
+}
+
+func (g *gate) interceptor() ent.Interceptor {
+	return ent.InterceptFunc(func(next ent.Querier) ent.Querier {
+		return ent.QuerierFunc(func(ctx context.Context, q ent.Query) (ent.Value, error) {
+			g.queries.Add(1)
+			v, err := next.Query(ctx, q)
+
+			g.mu.Lock()
+			closed, read, release := g.closed, g.read, g.release
+			g.mu.Unlock()
+			if closed {
+				read <- struct{}{}
+				<-release
+			}
+			return v, err
+		})
+	})
+}
+
+// newCache returns a cache in front of a private in-memory database, and
+// the gate its bookmark queries pass.
+func newCache(t *testing.T) (*ShortCodeCache, *ent.Client, *gate) {
+	t.Helper()
+
+	db, dialectName, err := database.Open("file:" + t.Name() + "?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := enttest.NewClient(t, enttest.WithOptions(ent.Driver(database.Driver(db, dialectName))))
+	t.Cleanup(func() { client.Close() })
+
+	c := NewShortCodeCache(client, DefaultSize, DefaultTTL, DefaultNegativeTTL)
+	client.Bookmark.Use(c.Hook())
+	g := &gate{}
+	client.Bookmark.Intercept(g.interceptor())
+	t.Cleanup(g.open)
+	return c, client, g
+}
+
+func createBookmark(t *testing.T, client *ent.Client, shortCode, url string) *ent.Bookmark {
+	t.Helper()
+
+	b, err := client.Bookmark.Create().SetTitle("Example").SetURL(url).SetShortCode(shortCode).Save(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestShortCodeCacheHitsAndMisses(t *testing.T) {
+	c, client, g := newCache(t)
+	ctx := context.Background()
+	createBookmark(t, client, "abc123", "https://example.com")
+
+	for range 3 {
+		b, err := c.Get(ctx, "abc123")
+		if err != nil || b.URL != "https://example.com" {
+			t.Fatalf("Get = %v, %v", b, err)
+		}
+	}
+	if got := g.queries.Load(); got != 1 {
+		t.Fatalf("%d queries, want 1", got)
+	}
+	if got, want := c.Stats(), (Stats{Hits: 2, Misses: 1, Size: 1}); got != want {
+		t.Fatalf("Stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestShortCodeCacheNegative(t *testing.T) {
+	c, client, g := newCache(t)
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.lru.now = func() time.Time { return now }
+
+	for range 2 {
+		if _, err := c.Get(ctx, "nope"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Get = %v, want ErrNotFound", err)
+		}
+	}
+	if got, want := c.Stats(), (Stats{NegativeHits: 1, Misses: 1, Size: 1}); got != want {
+		t.Fatalf("Stats = %+v, want %+v", got, want)
+	}
+
+	// Unknown codes are forgotten sooner than known ones
+	now = now.Add(DefaultNegativeTTL)
+	if _, err := c.Get(ctx, "nope"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get = %v, want ErrNotFound", err)
+	}
+	if got := g.queries.Load(); got != 2 {
+		t.Fatalf("%d queries, want the negative entry to expire", got)
+	}
+
+	// Creating the code drops the negative entry
+	createBookmark(t, client, "nope", "https://example.com")
+	if b, err := c.Get(ctx, "nope"); err != nil || b.ShortCode != "nope" {
+		t.Fatalf("Get after create = %v, %v", b, err)
+	}
+}
+
+func TestShortCodeCacheExpiry(t *testing.T) {
+	c, client, g := newCache(t)
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.lru.now = func() time.Time { return now }
+	createBookmark(t, client, "abc123", "https://example.com")
+
+	if _, err := c.Get(ctx, "abc123"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(DefaultTTL - time.Second)
+	if _, err := c.Get(ctx, "abc123"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Second)
+	if _, err := c.Get(ctx, "abc123"); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.queries.Load(); got != 2 {
+		t.Fatalf("%d queries, want one before and one after the TTL", got)
+	}
+}
+
+func TestShortCodeCacheCollapsesMisses(t *testing.T) {
+	c, client, g := newCache(t)
+	createBookmark(t, client, "abc123", "https://example.com")
+	g.close()
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Get(context.Background(), "abc123")
+			errs <- err
+		}()
+	}
+
+	<-g.read
+	for c.Stats().Misses < callers {
+		time.Sleep(time.Millisecond)
+	}
+	// Give the last callers time to join the running query
+	time.Sleep(50 * time.Millisecond)
+	g.open()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := g.queries.Load(); got != 1 {
+		t.Fatalf("%d queries, want 1", got)
+	}
+}
+
+func TestShortCodeCacheInvalidationRace(t *testing.T) {
+	c, client, g := newCache(t)
+	ctx := context.Background()
+	b := createBookmark(t, client, "abc123", "https://old.example.com")
+
+	// A lookup reads the old row, then stalls until after the update
+	g.close()
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Get(ctx, "abc123")
+		done <- err
+	}()
+	<-g.read
+
+	if err := client.Bookmark.UpdateOneID(b.ID).SetURL("https://new.example.com").Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	g.open()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.Get(ctx, "abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.URL != "https://new.example.com" {
+		t.Fatalf("URL = %q, the stale lookup was cached", got.URL)
+	}
+}
+
+func TestShortCodeCacheKeepsCachingAfterInvalidation(t *testing.T) {
+	c, client, g := newCache(t)
+	ctx := context.Background()
+	createBookmark(t, client, "abc123", "https://example.com")
+
+	c.Invalidate([]string{"abc123"}, nil)
+	for range 2 {
+		if _, err := c.Get(ctx, "abc123"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := g.queries.Load(); got != 1 {
+		t.Fatalf("%d queries, want 1", got)
+	}
+	if len(c.inFlight) != 0 {
+		t.Fatalf("%d codes still tracked as in flight", len(c.inFlight))
+	}
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](2)
+	c.Set("a", 1, time.Minute)
+	c.Set("b", 2, time.Minute)
+	// Reading a makes b the least recently used
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a missing")
+	}
+	c.Set("c", 3, time.Minute)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("b was not evicted")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if got, ok := c.Get(key); !ok || got != want {
+			t.Fatalf("Get(%q) = %d, %v, want %d", key, got, ok, want)
+		}
+	}
+	if c.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", c.Len())
+	}
+}
+
+func TestLRUExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewLRU[string, int](10)
+	c.now = func() time.Time { return now }
+
+	c.Set("short", 1, time.Second)
+	c.Set("long", 2, time.Minute)
+
+	now = now.Add(time.Second - time.Nanosecond)
+	if _, ok := c.Get("short"); !ok {
+		t.Fatal("entry expired before its TTL")
+	}
+	now = now.Add(time.Nanosecond)
+	if _, ok := c.Get("short"); ok {
+		t.Fatal("entry outlived its TTL")
+	}
+	if c.Len() != 1 {
+		t.Fatalf("Len = %d, want the expired entry removed", c.Len())
+	}
+
+	// Setting an entry again restarts its TTL
+	now = now.Add(50 * time.Second)
+	c.Set("long", 3, time.Minute)
+	now = now.Add(30 * time.Second)
+	if got, ok := c.Get("long"); !ok || got != 3 {
+		t.Fatalf("Get(long) = %d, %v, want 3", got, ok)
+	}
+}
+
+func TestLRUDeleteFunc(t *testing.T) {
+	c := NewLRU[string, int](10)
+	for i, key := range []string{"a", "b", "c", "d"} {
+		c.Set(key, i, time.Minute)
+	}
+	c.DeleteFunc(func(_ string, v int) bool { return v%2 == 0 })
+
+	for key, want := range map[string]bool{"a": false, "b": true, "c": false, "d": true} {
+		if _, ok := c.Get(key); ok != want {
+			t.Fatalf("Get(%q) found = %v, want %v", key, ok, want)
+		}
+	}
+}
@@ -3,53 +3,333 @@ package config
 import (
 	"context"
 	"database/sql"
-	"log"
-	"os"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"time"
 
 	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/migrate/migrations"
+	"bookmark-shortener/internal/analytics"
+	"bookmark-shortener/internal/cache"
+	"bookmark-shortener/internal/database"
+	"bookmark-shortener/internal/dbmigrate"
+	"bookmark-shortener/internal/lockout"
+	"bookmark-shortener/internal/logging"
+	"bookmark-shortener/internal/mail"
+	"bookmark-shortener/internal/metrics"
+	"bookmark-shortener/internal/ratelimit"
+	"bookmark-shortener/internal/secretbox"
+	"bookmark-shortener/internal/session"
+	"bookmark-shortener/internal/tracing"
+	"bookmark-shortener/internal/visits"
+)
 
-	"entgo.io/ent/dialect"
-	entsql "entgo.io/ent/dialect/sql"
-	_ "github.com/lib/pq"
+const (
+	AuthModeJWT     = "jwt"
+	AuthModeSession = "session"
 )
 
+// Config holds the server settings. Each field's tag names its key in a
+// config file; the environment variable is the upper-case form of the key.
+// Fields tagged secret are redacted when printed or logged, and url fields
+// have their password redacted.
 type Config struct {
-	DatabaseURL string
-	JWTSecret   string
-	Port        string
-	BaseURL     string
+	DatabaseURL  string        `config:"database_url,url"`
+	JWTSecret    string        `config:"jwt_secret,secret"`
+	Port         string        `config:"port"`
+	BaseURL      string        `config:"base_url"`
+	AuthMode     string        `config:"auth_mode"`
+	SessionStore string        `config:"session_store"`
+	RedisURL     string        `config:"redis_url,url"`
+	SessionTTL   time.Duration `config:"session_ttl"`
+	GeoIPFile    string        `config:"geoip_file"`
+	ClickSalt    string        `config:"click_salt,secret"`
+
+	VisitFlushInterval time.Duration `config:"visit_flush_interval"`
+	VisitBatchSize     int           `config:"visit_batch_size"`
+
+	CacheSize        int           `config:"cache_size"`
+	CacheTTL         time.Duration `config:"cache_ttl"`
+	CacheNegativeTTL time.Duration `config:"cache_negative_ttl"`
+
+	ReadTimeout     time.Duration `config:"read_timeout"`
+	WriteTimeout    time.Duration `config:"write_timeout"`
+	IdleTimeout     time.Duration `config:"idle_timeout"`
+	ShutdownDelay   time.Duration `config:"shutdown_delay"`
+	ShutdownTimeout time.Duration `config:"shutdown_timeout"`
+
+	// TrustedProxies lists the IPs or CIDRs of reverse proxies whose
+	// X-Forwarded-For, X-Forwarded-Proto and Forwarded headers are believed.
+	TrustedProxies []string `config:"trusted_proxies"`
+
+	// RateLimitStore is memory or redis (shared through REDIS_URL). The
+	// limits apply per IP to the auth endpoints and redirects, and per user
+	// to the bookmark API.
+	RateLimitStore    string          `config:"rate_limit_store"`
+	RateLimitAuth     ratelimit.Limit `config:"rate_limit_auth"`
+	RateLimitRedirect ratelimit.Limit `config:"rate_limit_redirect"`
+	RateLimitAPI      ratelimit.Limit `config:"rate_limit_api"`
+
+	// Failed logins per account are free up to LoginBackoffAfter, then
+	// delayed starting at LoginBackoff and doubling, until the account is
+	// locked for LoginLockout after LoginMaxFailures. Client IPs are locked
+	// after LoginMaxFailuresPerIP. A zero maximum disables the check.
+	LoginBackoffAfter     int           `config:"login_backoff_after"`
+	LoginBackoff          time.Duration `config:"login_backoff"`
+	LoginMaxFailures      int           `config:"login_max_failures"`
+	LoginMaxFailuresPerIP int           `config:"login_max_failures_per_ip"`
+	LoginLockout          time.Duration `config:"login_lockout"`
+
+	// Mailer is file, which writes messages to MailDir for development, or
+	// smtp.
+	Mailer       string `config:"mailer"`
+	MailFrom     string `config:"mail_from"`
+	MailDir      string `config:"mail_dir"`
+	SMTPHost     string `config:"smtp_host"`
+	SMTPPort     string `config:"smtp_port"`
+	SMTPUsername string `config:"smtp_username"`
+	SMTPPassword string `config:"smtp_password,secret"`
+
+	// PasswordResetURL is the page reset emails link to, with the token
+	// appended as a query parameter. When empty the email carries the bare
+	// token for POST /auth/password/reset.
+	PasswordResetTTL time.Duration `config:"password_reset_ttl"`
+	PasswordResetURL string        `config:"password_reset_url"`
+
+	// New accounts are mailed a verification link valid for
+	// EmailVerificationTTL. With RequireVerifiedEmail they cannot create
+	// bookmarks until they follow it.
+	EmailVerificationTTL time.Duration `config:"email_verification_ttl"`
+	RequireVerifiedEmail bool          `config:"require_verified_email"`
+
+	// TOTPEncryptionKey encrypts TOTP secrets at rest, as 32 hex-encoded
+	// bytes. Two-factor authentication is unavailable without it. After
+	// the password, users with it enabled get a challenge valid for
+	// MFAChallengeTTL to exchange together with a code.
+	TOTPEncryptionKey string        `config:"totp_encryption_key,secret"`
+	TOTPIssuer        string        `config:"totp_issuer"`
+	MFAChallengeTTL   time.Duration `config:"mfa_challenge_ttl"`
+
+	// MetricsPort serves /metrics on a separate admin listener; when empty
+	// it is served by the main router.
+	MetricsPort string `config:"metrics_port"`
+
+	// TraceExporter is none, stdout or otlp
+	TraceExporter string `config:"trace_exporter"`
+
+	LogFormat string `config:"log_format"`
+	LogLevel  string `config:"log_level"`
 }
 
-func New() *Config {
+// Default returns the configuration used for settings that are not set.
+func Default() *Config {
 	return &Config{
-		DatabaseURL: getEnv("DATABASE_URL", ""),
-		JWTSecret:   getEnv("JWT_SECRET", ""),
-		Port:        getEnv("PORT", "8080"),
-		BaseURL:     getEnv("BASE_URL", "http://127.0.0.1:8080"),
+		Port:         "8080",
+		BaseURL:      "http://127.0.0.1:8080",
+		AuthMode:     AuthModeJWT,
+		SessionStore: "memory",
+		RedisURL:     "redis://localhost:6379/0",
+		SessionTTL:   session.DefaultTTL,
+
+		VisitFlushInterval: visits.DefaultFlushInterval,
+		VisitBatchSize:     visits.DefaultBatchSize,
+
+		CacheSize:        cache.DefaultSize,
+		CacheTTL:         cache.DefaultTTL,
+		CacheNegativeTTL: cache.DefaultNegativeTTL,
+
+		ReadTimeout:     15 * time.Second,
+		WriteTimeout:    15 * time.Second,
+		IdleTimeout:     60 * time.Second,
+		ShutdownTimeout: 20 * time.Second,
+
+		RateLimitStore:    "memory",
+		RateLimitAuth:     ratelimit.Limit{Requests: 10, Period: time.Minute},
+		RateLimitRedirect: ratelimit.Limit{Requests: 600, Period: time.Minute},
+		RateLimitAPI:      ratelimit.Limit{Requests: 300, Period: time.Minute},
+
+		LoginBackoffAfter:     3,
+		LoginBackoff:          time.Second,
+		LoginMaxFailures:      10,
+		LoginMaxFailuresPerIP: 50,
+		LoginLockout:          15 * time.Minute,
+
+		Mailer:   "file",
+		MailFrom: "Bookmark Shortener <noreply@localhost>",
+		MailDir:  "mail",
+		SMTPPort: "587",
+
+		PasswordResetTTL: time.Hour,
+
+		EmailVerificationTTL: 48 * time.Hour,
+
+		TOTPIssuer:      "Bookmark Shortener",
+		MFAChallengeTTL: 5 * time.Minute,
+
+		TraceExporter: tracing.ExporterNone,
+
+		LogFormat: logging.FormatText,
+		LogLevel:  "info",
+	}
+}
+
+// LogValue lets the configuration be logged with secrets redacted.
+func (c *Config) LogValue() slog.Value {
+	v := reflect.ValueOf(c).Elem()
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		switch {
+		case f.secret:
+			attrs = append(attrs, slog.String(f.key, redactSecret(v.Field(f.index).String())))
+		case f.url:
+			attrs = append(attrs, slog.String(f.key, redactURL(v.Field(f.index).String())))
+		default:
+			attrs = append(attrs, slog.Any(f.key, v.Field(f.index).Interface()))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// redactURL hides the password of a connection URL. Values that don't parse
+// as URLs are hidden entirely, since they may be a DSN with a password.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return logging.Redacted
+	}
+	return u.Redacted()
+}
+
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return logging.Redacted
+}
+
+// OpenDB opens the database without checking its schema. The dialect is
+// picked from the DATABASE_URL scheme.
+func (c *Config) OpenDB() (*sql.DB, string, error) {
+	return database.Open(c.DatabaseURL)
+}
+
+// Migrator returns the versioned migrator for the configured database.
+func (c *Config) Migrator(db *sql.DB, dialectName string) (*dbmigrate.Migrator, error) {
+	dir, err := migrations.FS(dialectName)
+	if err != nil {
+		return nil, err
 	}
+	return dbmigrate.New(db, dialectName, dir)
 }
 
+// InitDB opens the database and returns an Ent client for it. It refuses to
+// start against a database with pending migrations instead of altering the
+// schema; run `go run ./cmd/migrate up` first.
 func (c *Config) InitDB() (*ent.Client, error) {
-	log.Println(c.DatabaseURL)
-	db, err := sql.Open("postgres", c.DatabaseURL)
+	db, dialectName, err := c.OpenDB()
 	if err != nil {
 		return nil, err
 	}
 
-	drv := entsql.OpenDB(dialect.Postgres, db)
-	client := ent.NewClient(ent.Driver(drv))
+	migrator, err := c.Migrator(db, dialectName)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := migrator.CheckCurrent(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	drv := database.Driver(db, dialectName)
+	drv = tracing.Driver(metrics.Driver(drv), dialectName)
+	return ent.NewClient(ent.Driver(drv)), nil
+}
+
+// InitSessionStore returns the store backing opaque session tokens, or nil
+// when the server authenticates with JWTs.
+func (c *Config) InitSessionStore() (session.Store, error) {
+	if c.AuthMode != AuthModeSession {
+		return nil, nil
+	}
+
+	switch c.SessionStore {
+	case "memory":
+		return session.NewMemoryStore(c.SessionTTL), nil
+	case "redis":
+		return session.NewRedisStore(c.RedisURL, c.SessionTTL)
+	default:
+		return nil, fmt.Errorf("unknown session store %q", c.SessionStore)
+	}
+}
 
-	// Test connection
-	if err := client.Schema.Create(context.Background()); err != nil {
+// InitRateLimitStore returns the store holding rate limit buckets.
+func (c *Config) InitRateLimitStore() (ratelimit.Store, error) {
+	switch c.RateLimitStore {
+	case "memory":
+		return ratelimit.NewMemoryStore(), nil
+	case "redis":
+		return ratelimit.NewRedisStore(c.RedisURL)
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", c.RateLimitStore)
+	}
+}
+
+// InitMailer returns the mailer for transactional emails.
+func (c *Config) InitMailer() (mail.Mailer, error) {
+	switch c.Mailer {
+	case "file":
+		return mail.NewFileMailer(c.MailDir, c.MailFrom)
+	case "smtp":
+		return mail.NewSMTPMailer(c.SMTPHost, c.SMTPPort, c.SMTPUsername, c.SMTPPassword, c.MailFrom), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer %q", c.Mailer)
+	}
+}
+
+// InitTOTPBox returns the box encrypting TOTP secrets, or nil when no key
+// is configured.
+func (c *Config) InitTOTPBox() (*secretbox.Box, error) {
+	if c.TOTPEncryptionKey == "" {
+		return nil, nil
+	}
+	key, err := secretbox.ParseKey(c.TOTPEncryptionKey)
+	if err != nil {
 		return nil, err
 	}
+	return secretbox.New(key)
+}
+
+// AccountLockout is the policy for failed logins to one account.
+func (c *Config) AccountLockout() lockout.Policy {
+	return lockout.Policy{
+		FreeFailures: c.LoginBackoffAfter,
+		MaxFailures:  c.LoginMaxFailures,
+		BaseDelay:    c.LoginBackoff,
+		Duration:     c.LoginLockout,
+	}
+}
 
-	return client, nil
+// IPLockout is the policy for failed logins from one client IP, which
+// locks without backing off first.
+func (c *Config) IPLockout() lockout.Policy {
+	return lockout.Policy{
+		FreeFailures: c.LoginMaxFailuresPerIP,
+		MaxFailures:  c.LoginMaxFailuresPerIP,
+		Duration:     c.LoginLockout,
+	}
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// InitGeoIP loads the country database used for click analytics, if one is
+// configured.
+func (c *Config) InitGeoIP() (analytics.GeoIP, error) {
+	if c.GeoIPFile == "" {
+		return analytics.NoGeoIP{}, nil
 	}
-	return defaultValue
+	return analytics.LoadFileGeoIP(c.GeoIPFile)
 }
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// field describes a tagged Config field.
+type field struct {
+	key    string
+	env    string
+	index  int
+	secret bool
+	url    bool
+}
+
+var fields = configFields()
+
+func configFields() []field {
+	t := reflect.TypeOf(Config{})
+	fs := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("config")
+		if !ok {
+			continue
+		}
+		key, opts, _ := strings.Cut(tag, ",")
+		fs = append(fs, field{
+			key:    key,
+			env:    strings.ToUpper(key),
+			index:  i,
+			secret: opts == "secret",
+			url:    opts == "url",
+		})
+	}
+	return fs
+}
+
+// Load reads the configuration with Read and validates it.
+func Load(path string) (*Config, error) {
+	cfg, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Read builds the configuration without validating it. Settings come from,
+// in increasing precedence, the defaults, the YAML or TOML file at path (if
+// path is not empty) and environment variables. Empty environment variables
+// count as unset. Every malformed value is reported, not just the first.
+func Read(path string) (*Config, error) {
+	cfg := Default()
+	v := reflect.ValueOf(cfg).Elem()
+
+	var errs []error
+	if path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range fields {
+			raw, ok := values[f.key]
+			if !ok {
+				continue
+			}
+			delete(values, f.key)
+			if err := setField(v.Field(f.index), raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", path, f.key, err))
+			}
+		}
+		for key := range values {
+			errs = append(errs, fmt.Errorf("%s: unknown setting %q", path, key))
+		}
+	}
+
+	for _, f := range fields {
+		raw := os.Getenv(f.env)
+		if raw == "" {
+			continue
+		}
+		if err := setField(v.Field(f.index), raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// readFile decodes a flat YAML or TOML file, picked by extension, into raw
+// values. Lists are joined with commas, like list environment variables.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		return nil, fmt.Errorf("%s: unsupported config file type %q, expected .yaml, .yml or .toml", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(doc))
+	for key, value := range doc {
+		switch value := value.(type) {
+		case map[string]any:
+			return nil, fmt.Errorf("%s: %s: nested settings are not supported", path, key)
+		case []any:
+			items := make([]string, len(value))
+			for i, item := range value {
+				items[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(items, ",")
+		case nil:
+			values[key] = ""
+		default:
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return values, nil
+}
+
+func setField(v reflect.Value, raw string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(raw))
+	}
+	switch v.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q, expected a value such as 30s or 5m", raw)
+		}
+		v.SetInt(int64(d))
+	case int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(int64(n))
+	case bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q, expected true or false", raw)
+		}
+		v.SetBool(b)
+	case []string:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	case string:
+		v.SetString(raw)
+	default:
+		panic(fmt.Sprintf("config: unsupported field type %s", v.Type()))
+	}
+	return nil
+}
+
+// Print writes the configuration as environment variables, with secrets
+// redacted.
+func (c *Config) Print(w io.Writer) error {
+	v := reflect.ValueOf(c).Elem()
+	for _, f := range fields {
+		var value string
+		switch x := v.Field(f.index).Interface().(type) {
+		case string:
+			value = x
+			if f.secret {
+				value = redactSecret(x)
+			} else if f.url {
+				value = redactURL(x)
+			}
+		case []string:
+			value = strings.Join(x, ",")
+		default:
+			value = fmt.Sprint(x)
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s\n", f.env, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
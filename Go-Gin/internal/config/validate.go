@@ -0,0 +1,190 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"bookmark-shortener/internal/baseurl"
+	"bookmark-shortener/internal/database"
+	"bookmark-shortener/internal/logging"
+	"bookmark-shortener/internal/secretbox"
+	"bookmark-shortener/internal/tracing"
+)
+
+// MinSecretLength is the minimum length in bytes of JWT_SECRET and, when
+// set, CLICK_SALT. 32 random bytes match the HMAC-SHA256 key size.
+const MinSecretLength = 32
+
+// Validate reports every invalid setting, named by its environment
+// variable.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if _, err := database.Dialect(c.DatabaseURL); err != nil {
+		errs = append(errs, err)
+	}
+	if c.JWTSecret == "" {
+		fail("JWT_SECRET is not set")
+	} else if len(c.JWTSecret) < MinSecretLength {
+		fail("JWT_SECRET must be at least %d bytes, generate one with `openssl rand -hex 32`", MinSecretLength)
+	}
+	if c.ClickSalt != "" && len(c.ClickSalt) < MinSecretLength {
+		fail("CLICK_SALT must be at least %d bytes", MinSecretLength)
+	}
+
+	if !validPort(c.Port) {
+		fail("PORT %q is not a valid port", c.Port)
+	}
+	if c.MetricsPort != "" {
+		if !validPort(c.MetricsPort) {
+			fail("METRICS_PORT %q is not a valid port", c.MetricsPort)
+		} else if c.MetricsPort == c.Port {
+			fail("METRICS_PORT must differ from PORT, leave it empty to serve /metrics on PORT")
+		}
+	}
+	if u, err := url.Parse(c.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		fail("BASE_URL %q must be an absolute http or https URL such as https://sho.rt", c.BaseURL)
+	}
+	if _, err := baseurl.ParseProxies(c.TrustedProxies); err != nil {
+		fail("TRUSTED_PROXIES: %v", err)
+	}
+
+	switch c.AuthMode {
+	case AuthModeJWT, AuthModeSession:
+	default:
+		fail("AUTH_MODE %q must be %s or %s", c.AuthMode, AuthModeJWT, AuthModeSession)
+	}
+	usesRedis := false
+	if c.AuthMode == AuthModeSession {
+		switch c.SessionStore {
+		case "memory":
+		case "redis":
+			usesRedis = true
+		default:
+			fail("SESSION_STORE %q must be memory or redis", c.SessionStore)
+		}
+	}
+	switch c.RateLimitStore {
+	case "memory":
+	case "redis":
+		usesRedis = true
+	default:
+		fail("RATE_LIMIT_STORE %q must be memory or redis", c.RateLimitStore)
+	}
+	if usesRedis {
+		if u, err := url.Parse(c.RedisURL); err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") {
+			fail("REDIS_URL must be a redis:// or rediss:// URL")
+		}
+	}
+
+	switch c.Mailer {
+	case "file":
+		if c.MailDir == "" {
+			fail("MAIL_DIR is not set")
+		}
+	case "smtp":
+		if c.SMTPHost == "" {
+			fail("SMTP_HOST is required when MAILER is smtp")
+		}
+		if !validPort(c.SMTPPort) {
+			fail("SMTP_PORT %q is not a valid port", c.SMTPPort)
+		}
+	default:
+		fail("MAILER %q must be file or smtp", c.Mailer)
+	}
+	if _, err := mail.ParseAddress(c.MailFrom); err != nil {
+		fail("MAIL_FROM %q must be an address such as Shortener <noreply@sho.rt>", c.MailFrom)
+	}
+	if c.PasswordResetURL != "" {
+		if u, err := url.Parse(c.PasswordResetURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Fragment != "" {
+			fail("PASSWORD_RESET_URL %q must be an absolute http or https URL", c.PasswordResetURL)
+		}
+	}
+
+	if c.TOTPEncryptionKey != "" {
+		if _, err := secretbox.ParseKey(c.TOTPEncryptionKey); err != nil {
+			fail("TOTP_ENCRYPTION_KEY: %v", err)
+		}
+	}
+	if c.TOTPIssuer == "" || strings.Contains(c.TOTPIssuer, ":") {
+		fail("TOTP_ISSUER must be set and must not contain a colon")
+	}
+
+	durations := []struct {
+		name      string
+		value     time.Duration
+		allowZero bool
+	}{
+		{"SESSION_TTL", c.SessionTTL, false},
+		{"VISIT_FLUSH_INTERVAL", c.VisitFlushInterval, false},
+		{"CACHE_TTL", c.CacheTTL, false},
+		{"CACHE_NEGATIVE_TTL", c.CacheNegativeTTL, true},
+		{"READ_TIMEOUT", c.ReadTimeout, true},
+		{"WRITE_TIMEOUT", c.WriteTimeout, true},
+		{"IDLE_TIMEOUT", c.IdleTimeout, true},
+		{"SHUTDOWN_DELAY", c.ShutdownDelay, true},
+		{"SHUTDOWN_TIMEOUT", c.ShutdownTimeout, false},
+		{"PASSWORD_RESET_TTL", c.PasswordResetTTL, false},
+		{"EMAIL_VERIFICATION_TTL", c.EmailVerificationTTL, false},
+		{"MFA_CHALLENGE_TTL", c.MFAChallengeTTL, false},
+	}
+	for _, d := range durations {
+		switch {
+		case d.value < 0:
+			fail("%s must not be negative", d.name)
+		case d.value == 0 && !d.allowZero:
+			fail("%s must be positive", d.name)
+		}
+	}
+	if c.LoginMaxFailures < 0 || c.LoginMaxFailuresPerIP < 0 {
+		fail("LOGIN_MAX_FAILURES and LOGIN_MAX_FAILURES_PER_IP must not be negative")
+	}
+	if c.LoginBackoffAfter < 0 {
+		fail("LOGIN_BACKOFF_AFTER must not be negative")
+	}
+	if c.LoginMaxFailures > 0 || c.LoginMaxFailuresPerIP > 0 {
+		if c.LoginLockout <= 0 {
+			fail("LOGIN_LOCKOUT must be positive")
+		}
+		if c.LoginBackoff <= 0 {
+			fail("LOGIN_BACKOFF must be positive")
+		}
+	}
+	if c.VisitBatchSize < 1 {
+		fail("VISIT_BATCH_SIZE must be positive")
+	}
+	if c.CacheSize < 1 {
+		fail("CACHE_SIZE must be positive")
+	}
+
+	switch c.TraceExporter {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		fail("TRACE_EXPORTER %q must be none, stdout or otlp", c.TraceExporter)
+	}
+	switch c.LogFormat {
+	case logging.FormatText, logging.FormatJSON:
+	default:
+		fail("LOG_FORMAT %q must be text or json", c.LogFormat)
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		fail("LOG_LEVEL %q must be debug, info, warn or error", c.LogLevel)
+	}
+
+	return errors.Join(errs...)
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
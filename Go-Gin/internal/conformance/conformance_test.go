@@ -0,0 +1,163 @@
+package conformance_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/enttest"
+	"bookmark-shortener/internal/config"
+	"bookmark-shortener/internal/conformance"
+	"bookmark-shortener/internal/database"
+	"bookmark-shortener/internal/server"
+
+	"github.com/gin-gonic/gin"
+)
+
+// startGoServer runs the Go backend in-process on an in-memory database.
+func startGoServer(t *testing.T) string {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	t.Setenv("DATABASE_URL", "file:"+t.Name()+"?mode=memory&cache=shared")
+	t.Setenv("JWT_SECRET", "conformance-test-secret-0123456789")
+	t.Setenv("AUTH_MODE", config.AuthModeJWT)
+	t.Setenv("CLICK_SALT", "conformance-test-salt-0123456789ab")
+	// The suite registers more users from one IP than the auth limit allows
+	t.Setenv("RATE_LIMIT_AUTH", "off")
+	t.Setenv("MAIL_DIR", t.TempDir())
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, dialectName, err := cfg.OpenDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := enttest.NewClient(t, enttest.WithOptions(ent.Driver(database.Driver(db, dialectName))))
+	t.Cleanup(func() { client.Close() })
+
+	router, err := server.NewRouter(cfg, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { router.Close(context.Background()) })
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestDefaultSuitePassesAgainstGo(t *testing.T) {
+	suite, err := conformance.DefaultSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := conformance.Target{Name: "go", BaseURL: startGoServer(t)}
+	results := conformance.NewRunner(5*time.Second).Run(context.Background(), target, suite)
+	if len(results) == 0 {
+		t.Fatal("suite has no steps")
+	}
+	for _, res := range results {
+		if res.Outcome != conformance.Pass {
+			t.Errorf("%s / %s: %s %v", res.Scenario, res.Step, res.Outcome, res.Problems)
+		}
+	}
+}
+
+func TestReportsDivergences(t *testing.T) {
+	suite, err := conformance.LoadSuite(strings.NewReader(`
+scenarios:
+  - name: register
+    steps:
+      - name: new user
+        request:
+          method: POST
+          path: /auth/register
+          json: {email: "{{run}}@example.com", password: password123}
+        expect:
+          status: 201
+          json:
+            email: "{{run}}@example.com"
+            id: <string>
+        capture: {id: id}
+      - name: duplicate
+        request:
+          method: POST
+          path: /auth/register
+          json: {email: "{{run}}@example.com", password: password123}
+        expect:
+          status: 409
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A backend that accepts everything and returns the wrong shape
+	lenient := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"user":{"id":1}}`))
+	}))
+	defer lenient.Close()
+
+	runner := conformance.NewRunner(5 * time.Second)
+	var report conformance.Report
+	for _, target := range []conformance.Target{
+		{Name: "go", BaseURL: startGoServer(t)},
+		{Name: "lenient", BaseURL: lenient.URL},
+	} {
+		report.Add(target, runner.Run(context.Background(), target, suite))
+	}
+
+	if !report.Failed() {
+		t.Fatal("report should fail")
+	}
+	if got := report.Results[0]; got[0].Outcome != conformance.Pass || got[1].Outcome != conformance.Pass {
+		t.Fatalf("go results: %+v", got)
+	}
+	got := report.Results[1]
+	if got[0].Outcome != conformance.Fail || got[1].Outcome != conformance.Skip {
+		t.Fatalf("lenient results: %+v", got)
+	}
+	want := []string{"status 200, want 201", "email: missing", "id: missing", "capture id: id is missing"}
+	if strings.Join(got[0].Problems, "; ") != strings.Join(want, "; ") {
+		t.Fatalf("problems = %q, want %q", got[0].Problems, want)
+	}
+
+	var table bytes.Buffer
+	if err := report.WriteTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"GO", "LENIENT", "lenient: 0/2 steps passed", "lenient: register / new user", "status 200, want 201"} {
+		if !strings.Contains(table.String(), s) {
+			t.Errorf("table does not contain %q:\n%s", s, table.String())
+		}
+	}
+
+	var junit bytes.Buffer
+	if err := report.WriteJUnit(&junit); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Suites []struct {
+			Name     string `xml:"name,attr"`
+			Tests    int    `xml:"tests,attr"`
+			Failures int    `xml:"failures,attr"`
+			Skipped  int    `xml:"skipped,attr"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(junit.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JUnit XML: %v\n%s", err, junit.String())
+	}
+	if len(doc.Suites) != 2 || doc.Suites[0].Failures != 0 || doc.Suites[1].Failures != 1 || doc.Suites[1].Skipped != 1 {
+		t.Fatalf("unexpected JUnit suites %+v", doc.Suites)
+	}
+}
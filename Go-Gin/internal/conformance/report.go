@@ -0,0 +1,164 @@
+package conformance
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Report holds the results of one suite run against several targets.
+type Report struct {
+	Targets []Target
+	// Results has one entry per target, in the order of Targets. Every
+	// entry lists the same steps in the same order.
+	Results [][]StepResult
+}
+
+// Add records the results for target.
+func (r *Report) Add(target Target, results []StepResult) {
+	r.Targets = append(r.Targets, target)
+	r.Results = append(r.Results, results)
+}
+
+// Failed reports whether any step failed or was skipped on any target.
+func (r *Report) Failed() bool {
+	for _, results := range r.Results {
+		for _, res := range results {
+			if res.Outcome != Pass {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WriteTable writes one row per step with a column per target, followed by
+// the details of every failure.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := []string{"SCENARIO", "STEP"}
+	for _, t := range r.Targets {
+		header = append(header, strings.ToUpper(t.Name))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for i := range r.steps() {
+		row := []string{r.Results[0][i].Scenario, r.Results[0][i].Step}
+		for _, results := range r.Results {
+			row = append(row, string(results[i].Outcome))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	for ti, t := range r.Targets {
+		passed := 0
+		for _, res := range r.Results[ti] {
+			if res.Outcome == Pass {
+				passed++
+			}
+		}
+		fmt.Fprintf(w, "%s: %d/%d steps passed\n", t.Name, passed, len(r.Results[ti]))
+	}
+
+	divergences := false
+	for ti, t := range r.Targets {
+		for _, res := range r.Results[ti] {
+			if res.Outcome != Fail {
+				continue
+			}
+			if !divergences {
+				fmt.Fprintln(w, "\nDivergences:")
+				divergences = true
+			}
+			fmt.Fprintf(w, "  %s: %s / %s\n", t.Name, res.Scenario, res.Step)
+			for _, p := range res.Problems {
+				fmt.Fprintf(w, "      %s\n", p)
+			}
+		}
+	}
+	return nil
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes a JUnit XML document with a test suite per target and a
+// test case per step.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	var doc junitSuites
+	for ti, t := range r.Targets {
+		suite := junitSuite{Name: t.Name, Tests: len(r.Results[ti])}
+		var total float64
+		for _, res := range r.Results[ti] {
+			tc := junitCase{
+				Name:      res.Step,
+				ClassName: t.Name + "." + res.Scenario,
+				Time:      fmt.Sprintf("%.3f", res.Duration.Seconds()),
+			}
+			total += res.Duration.Seconds()
+			switch res.Outcome {
+			case Fail:
+				suite.Failures++
+				tc.Failure = &junitFailure{
+					Message: res.Problems[0],
+					Text:    strings.Join(res.Problems, "\n"),
+				}
+			case Skip:
+				suite.Skipped++
+				tc.Skipped = &struct{}{}
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		suite.Time = fmt.Sprintf("%.3f", total)
+		doc.Suites = append(doc.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// steps returns the number of steps per target.
+func (r *Report) steps() int {
+	if len(r.Results) == 0 {
+		return 0
+	}
+	return len(r.Results[0])
+}
@@ -0,0 +1,315 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Target is a backend under test.
+type Target struct {
+	Name    string
+	BaseURL string
+}
+
+// Outcome of a single step.
+type Outcome string
+
+const (
+	Pass Outcome = "pass"
+	Fail Outcome = "fail"
+	Skip Outcome = "skip"
+)
+
+// StepResult is the outcome of one step against one target.
+type StepResult struct {
+	Scenario string
+	Step     string
+	Outcome  Outcome
+	// Problems lists every way the response differed from the expectation.
+	Problems []string
+	Duration time.Duration
+}
+
+// Runner executes suites. Redirects are never followed so they can be
+// checked.
+type Runner struct {
+	client *http.Client
+}
+
+func NewRunner(timeout time.Duration) *Runner {
+	return &Runner{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Run executes every scenario of suite against target, in order.
+func (r *Runner) Run(ctx context.Context, target Target, suite *Suite) []StepResult {
+	var results []StepResult
+	for _, sc := range suite.Scenarios {
+		vars := map[string]string{"run": runID()}
+		failed := false
+		for _, st := range sc.Steps {
+			res := StepResult{Scenario: sc.Name, Step: st.Name, Outcome: Skip}
+			if !failed {
+				start := time.Now()
+				res.Problems = r.runStep(ctx, target, st, vars)
+				res.Duration = time.Since(start)
+				res.Outcome = Pass
+				if len(res.Problems) > 0 {
+					res.Outcome = Fail
+					failed = true
+				}
+			}
+			results = append(results, res)
+		}
+	}
+	return results
+}
+
+func (r *Runner) runStep(ctx context.Context, target Target, st Step, vars map[string]string) []string {
+	var body io.Reader
+	if st.Request.JSON != nil {
+		data, err := json.Marshal(expand(st.Request.JSON, vars))
+		if err != nil {
+			return []string{fmt.Sprintf("encode request body: %v", err)}
+		}
+		body = bytes.NewReader(data)
+	}
+
+	url := strings.TrimRight(target.BaseURL, "/") + expandString(st.Request.Path, vars)
+	req, err := http.NewRequestWithContext(ctx, st.Request.Method, url, body)
+	if err != nil {
+		return []string{fmt.Sprintf("build request: %v", err)}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if st.Request.Auth != "" {
+		req.Header.Set("Authorization", "Bearer "+expandString(st.Request.Auth, vars))
+	}
+	for name, value := range st.Request.Headers {
+		req.Header.Set(name, expandString(value, vars))
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return []string{fmt.Sprintf("request failed: %v", err)}
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return []string{fmt.Sprintf("read response: %v", err)}
+	}
+
+	var problems []string
+	if resp.StatusCode != st.Expect.Status {
+		problems = append(problems, fmt.Sprintf("status %d, want %d", resp.StatusCode, st.Expect.Status))
+	}
+	for _, name := range slices.Sorted(maps.Keys(st.Expect.Headers)) {
+		want := st.Expect.Headers[name]
+		values := resp.Header.Values(name)
+		var got any
+		if len(values) > 0 {
+			got = values[0]
+		}
+		if p := match(expandString(want, vars), got, len(values) > 0); p != "" {
+			problems = append(problems, fmt.Sprintf("header %s: %s", name, p))
+		}
+	}
+
+	var doc any
+	if len(st.Expect.JSON) > 0 || len(st.Capture) > 0 {
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return append(problems, fmt.Sprintf("response is not JSON: %.80q", data))
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(st.Expect.JSON)) {
+		got, ok := lookup(doc, path)
+		if p := match(expand(st.Expect.JSON[path], vars), got, ok); p != "" {
+			problems = append(problems, fmt.Sprintf("%s: %s", path, p))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(st.Capture)) {
+		got, ok := lookup(doc, st.Capture[name])
+		if !ok {
+			problems = append(problems, fmt.Sprintf("capture %s: %s is missing", name, st.Capture[name]))
+			continue
+		}
+		vars[name] = scalarString(got)
+	}
+	return problems
+}
+
+// match compares a response value with an expectation and describes the
+// mismatch, or returns "" if it matches.
+func match(want, got any, present bool) string {
+	if s, ok := want.(string); ok && strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">") {
+		if s == "<absent>" {
+			if present {
+				return fmt.Sprintf("got %s, want absent", describe(got))
+			}
+			return ""
+		}
+		if !present {
+			return "missing"
+		}
+		var ok bool
+		switch s {
+		case "<present>":
+			ok = true
+		case "<string>":
+			_, ok = got.(string)
+		case "<nonempty>":
+			str, isString := got.(string)
+			ok = isString && str != ""
+		case "<number>":
+			_, ok = got.(float64)
+		case "<bool>":
+			_, ok = got.(bool)
+		case "<array>":
+			_, ok = got.([]any)
+		case "<object>":
+			_, ok = got.(map[string]any)
+		case "<null>":
+			ok = got == nil
+		default:
+			return fmt.Sprintf("unknown matcher %s", s)
+		}
+		if !ok {
+			return fmt.Sprintf("got %s, want %s", describe(got), s)
+		}
+		return ""
+	}
+
+	if !present {
+		return "missing"
+	}
+	if !reflect.DeepEqual(normalize(want), got) {
+		return fmt.Sprintf("got %s, want %s", describe(got), describe(normalize(want)))
+	}
+	return ""
+}
+
+// lookup resolves a dotted path in a decoded JSON document. Numeric
+// segments index arrays, "#" yields the length of an array and "." is the
+// document itself.
+func lookup(doc any, path string) (any, bool) {
+	if path == "." {
+		return doc, true
+	}
+	cur := doc
+	for _, seg := range strings.Split(path, ".") {
+		switch v := cur.(type) {
+		case map[string]any:
+			next, ok := v[seg]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []any:
+			if seg == "#" {
+				cur = float64(len(v))
+				continue
+			}
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			cur = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+var varPattern = regexp.MustCompile(`{{\s*(\w+)\s*}}`)
+
+// expandString substitutes {{name}} references. Unknown variables are left
+// in place so the mismatch shows up in the report.
+func expandString(s string, vars map[string]string) string {
+	return varPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := varPattern.FindStringSubmatch(ref)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// expand substitutes variables in every string of a decoded YAML value.
+func expand(v any, vars map[string]string) any {
+	switch v := v.(type) {
+	case string:
+		return expandString(v, vars)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, elem := range v {
+			out[k] = expand(elem, vars)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, elem := range v {
+			out[i] = expand(elem, vars)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// normalize converts a YAML value to the types encoding/json decodes into.
+func normalize(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func describe(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	if len(data) > 80 {
+		return string(data[:77]) + "..."
+	}
+	return string(data)
+}
+
+func scalarString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return describe(v)
+}
+
+func runID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,97 @@
+// Package conformance checks that a backend implements the bookmark API
+// contract. Scenarios are declared in YAML and run against any base URL, so
+// the Go, Express and FastAPI servers can be compared side by side.
+package conformance
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed suite.yaml
+var defaultSuite []byte
+
+// Suite is an ordered list of independent scenarios.
+type Suite struct {
+	Scenarios []Scenario `yaml:"scenarios"`
+}
+
+// Scenario is a sequence of steps sharing captured variables. A failing
+// step skips the rest of its scenario.
+type Scenario struct {
+	Name  string `yaml:"name"`
+	Steps []Step `yaml:"steps"`
+}
+
+// Step sends one request and checks the response.
+//
+// Strings in the request and expectations may reference variables as
+// {{name}}. {{run}} is unique per scenario run; other variables come from
+// Capture, which maps a variable name to a JSON path in the response body.
+type Step struct {
+	Name    string            `yaml:"name"`
+	Request Request           `yaml:"request"`
+	Expect  Expect            `yaml:"expect"`
+	Capture map[string]string `yaml:"capture"`
+}
+
+type Request struct {
+	Method  string            `yaml:"method"`
+	Path    string            `yaml:"path"`
+	Headers map[string]string `yaml:"headers"`
+	// Auth is sent as a bearer token in the Authorization header.
+	Auth string `yaml:"auth"`
+	JSON any    `yaml:"json"`
+}
+
+// Expect describes the response. JSON maps dotted paths ("0.id", "#" for
+// the length of an array, "." for the whole body) to either a literal value
+// or one of the matchers <string>, <number>, <bool>, <array>, <object>,
+// <null>, <present>, <absent> and <nonempty>. Headers accept the same
+// matchers.
+type Expect struct {
+	Status  int               `yaml:"status"`
+	Headers map[string]string `yaml:"headers"`
+	JSON    map[string]any    `yaml:"json"`
+}
+
+// DefaultSuite returns the built-in API contract.
+func DefaultSuite() (*Suite, error) {
+	return parseSuite(defaultSuite)
+}
+
+// LoadSuite reads a suite in the YAML format of suite.yaml.
+func LoadSuite(r io.Reader) (*Suite, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseSuite(data)
+}
+
+func parseSuite(data []byte) (*Suite, error) {
+	var s Suite
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse suite: %w", err)
+	}
+	for i, sc := range s.Scenarios {
+		if sc.Name == "" {
+			return nil, fmt.Errorf("scenario %d has no name", i+1)
+		}
+		for j, st := range sc.Steps {
+			if st.Name == "" {
+				return nil, fmt.Errorf("scenario %q: step %d has no name", sc.Name, j+1)
+			}
+			if st.Request.Method == "" || st.Request.Path == "" {
+				return nil, fmt.Errorf("scenario %q: step %q needs a method and path", sc.Name, st.Name)
+			}
+			if st.Expect.Status == 0 {
+				return nil, fmt.Errorf("scenario %q: step %q has no expected status", sc.Name, st.Name)
+			}
+		}
+	}
+	return &s, nil
+}
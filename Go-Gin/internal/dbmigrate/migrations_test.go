@@ -0,0 +1,65 @@
+package dbmigrate_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"bookmark-shortener/ent/migrate"
+	"bookmark-shortener/ent/migrate/migrations"
+	"bookmark-shortener/internal/database"
+	"bookmark-shortener/internal/dbmigrate"
+)
+
+// TestMigrationsMatchSchema applies the shipped migrations and checks that
+// the result is the schema Ent expects, then reverts them all. Postgres runs
+// when TEST_POSTGRES_URL points at an empty database.
+func TestMigrationsMatchSchema(t *testing.T) {
+	urls := map[string]string{"sqlite": "sqlite://file:" + t.Name() + "?mode=memory&cache=shared"}
+	if url := os.Getenv("TEST_POSTGRES_URL"); url != "" {
+		urls["postgres"] = url
+	}
+
+	for name, url := range urls {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			db, dialectName, err := database.Open(url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { db.Close() })
+			dir, err := migrations.FS(dialectName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := dbmigrate.New(db, dialectName, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			done, err := m.Up(ctx, 0)
+			if err != nil {
+				t.Fatalf("Up = %v after applying %v", err, done)
+			}
+			t.Cleanup(func() { m.Down(context.Background(), len(done)) })
+			if err := m.CheckCurrent(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			var changes strings.Builder
+			schema := migrate.NewSchema(database.Driver(db, dialectName))
+			if err := schema.WriteTo(ctx, &changes); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(changes.String()); got != "" {
+				t.Fatalf("the migrations don't produce the Ent schema, missing:\n%s", got)
+			}
+
+			reverted, err := m.Down(ctx, len(done))
+			if err != nil || len(reverted) != len(done) {
+				t.Fatalf("Down = %d migrations, %v, want %d", len(reverted), err, len(done))
+			}
+		})
+	}
+}
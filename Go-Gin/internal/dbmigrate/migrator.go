@@ -0,0 +1,335 @@
+package dbmigrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect"
+)
+
+// RevisionsTable records which migrations have been applied.
+const RevisionsTable = "schema_revisions"
+
+// advisoryLockID serialises concurrent migrators on Postgres.
+const advisoryLockID = 0x626f6f6b6d61726b
+
+var ErrPending = errors.New("database has pending migrations")
+
+type Migration struct {
+	Version string
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies and reverts versioned migrations, tracking them in the
+// revisions table. Every migration runs in its own transaction together with
+// its revision row, so a failed migration leaves no trace.
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	migrations []Migration
+}
+
+// New loads the golang-migrate style files (<version>_<name>.up.sql and
+// <version>_<name>.down.sql) from dir.
+func New(db *sql.DB, dialectName string, dir fs.FS) (*Migrator, error) {
+	ups, err := fs.Glob(dir, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ups)
+
+	migrations := make([]Migration, 0, len(ups))
+	for _, upFile := range ups {
+		base := strings.TrimSuffix(upFile, ".up.sql")
+		version, name, _ := strings.Cut(base, "_")
+
+		up, err := fs.ReadFile(dir, upFile)
+		if err != nil {
+			return nil, err
+		}
+		down, err := fs.ReadFile(dir, base+".down.sql")
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    name,
+			Up:      string(up),
+			Down:    string(down),
+		})
+	}
+
+	return &Migrator{db: db, dialect: dialectName, migrations: migrations}, nil
+}
+
+// Status lists every known migration and when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		statuses[i] = Status{Migration: mig}
+		if at, ok := applied[mig.Version]; ok {
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// Pending returns the migrations that have not been applied yet.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending = append(pending, s.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// CheckCurrent returns an error wrapping ErrPending unless every migration
+// has been applied.
+func (m *Migrator) CheckCurrent(ctx context.Context) error {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d not applied, starting with %s", ErrPending, len(pending), pending[0])
+	}
+	return nil
+}
+
+// Up applies up to n pending migrations in order, or all of them if n <= 0.
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 && n < len(pending) {
+		pending = pending[:n]
+	}
+
+	var done []Migration
+	for _, mig := range pending {
+		err := m.inTx(ctx, func(tx *sql.Tx) error {
+			if err := m.ensureRevisionsTable(ctx, tx); err != nil {
+				return err
+			}
+			applied, err := m.applied(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if _, ok := applied[mig.Version]; ok {
+				// Applied by a concurrent migrator in the meantime
+				return nil
+			}
+			if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+				return err
+			}
+			return m.insertRevision(ctx, tx, mig)
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %s: %w", mig, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Down reverts the n most recently applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(statuses) - 1; i >= 0 && len(done) < n; i-- {
+		mig := statuses[i].Migration
+		if statuses[i].AppliedAt == nil {
+			continue
+		}
+		if strings.TrimSpace(mig.Down) == "" {
+			return done, fmt.Errorf("migration %s has no down migration", mig)
+		}
+
+		err := m.inTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, "DELETE FROM "+RevisionsTable+" WHERE version = "+m.placeholder(1), mig.Version)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %s: %w", mig, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Baseline marks every migration up to and including version as applied
+// without running it, for databases whose schema was created before
+// versioned migrations were introduced.
+func (m *Migrator) Baseline(ctx context.Context, version string) ([]Migration, error) {
+	found := false
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown migration version %s", version)
+	}
+
+	var done []Migration
+	err := m.inTx(ctx, func(tx *sql.Tx) error {
+		if err := m.ensureRevisionsTable(ctx, tx); err != nil {
+			return err
+		}
+		applied, err := m.applied(ctx, tx)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if mig.Version > version {
+				break
+			}
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.insertRevision(ctx, tx, mig); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// applied returns when each applied migration was applied. It only reads,
+// so checking a database on startup never changes it; without a revisions
+// table nothing has been applied.
+func (m *Migrator) applied(ctx context.Context, q queryer) (map[string]time.Time, error) {
+	exists, err := m.revisionsTableExists(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[string]time.Time)
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := q.QueryContext(ctx, "SELECT version, applied_at FROM "+RevisionsTable)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version string
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) revisionsTableExists(ctx context.Context, q queryer) (bool, error) {
+	query := "SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = ?"
+	if m.dialect == dialect.Postgres {
+		query = "SELECT to_regclass($1) IS NOT NULL"
+	}
+	rows, err := q.QueryContext(ctx, query, RevisionsTable)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	var exists bool
+	if rows.Next() {
+		if err := rows.Scan(&exists); err != nil {
+			return false, err
+		}
+	}
+	return exists, rows.Err()
+}
+
+func (m *Migrator) ensureRevisionsTable(ctx context.Context, q queryer) error {
+	_, err := q.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+RevisionsTable+" ("+
+		"version VARCHAR(64) NOT NULL PRIMARY KEY, "+
+		"description VARCHAR(255) NOT NULL, "+
+		"applied_at TIMESTAMP NOT NULL)")
+	return err
+}
+
+func (m *Migrator) insertRevision(ctx context.Context, tx *sql.Tx, mig Migration) error {
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO "+RevisionsTable+" (version, description, applied_at) VALUES ("+m.placeholder(1)+", "+m.placeholder(2)+", "+m.placeholder(3)+")",
+		mig.Version, mig.Name, time.Now().UTC())
+	return err
+}
+
+func (m *Migrator) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if m.dialect == dialect.Postgres {
+		// Held until the transaction ends, so two instances starting at the
+		// same time don't apply the same migration twice
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", advisoryLockID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) placeholder(i int) string {
+	if m.dialect == dialect.Postgres {
+		return fmt.Sprintf("$%d", i)
+	}
+	return "?"
+}
+
+func (mig Migration) String() string {
+	return mig.Version + "_" + mig.Name
+}
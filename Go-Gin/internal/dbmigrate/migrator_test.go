@@ -0,0 +1,183 @@
+package dbmigrate_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"bookmark-shortener/internal/database"
+	"bookmark-shortener/internal/dbmigrate"
+)
+
+var testMigrations = fstest.MapFS{
+	"001_links.up.sql":   {Data: []byte("CREATE TABLE links (id INTEGER PRIMARY KEY);")},
+	"001_links.down.sql": {Data: []byte("DROP TABLE links;")},
+	"002_tags.up.sql":    {Data: []byte("CREATE TABLE tags (id INTEGER PRIMARY KEY);")},
+	"002_tags.down.sql":  {Data: []byte("DROP TABLE tags;")},
+}
+
+// newMigrator returns a migrator over migrations and a private in-memory
+// SQLite database.
+func newMigrator(t *testing.T, migrations fstest.MapFS) (*dbmigrate.Migrator, *sql.DB) {
+	t.Helper()
+
+	db, dialectName, err := database.Open("file:" + t.Name() + "?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := dbmigrate.New(db, dialectName, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, db
+}
+
+// tables returns the names of the tables in db.
+func tables(t *testing.T, db *sql.DB) string {
+	t.Helper()
+
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' ORDER BY name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ",")
+}
+
+// applied returns the applied migrations as reported by Status.
+func applied(t *testing.T, m *dbmigrate.Migrator) string {
+	t.Helper()
+
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []string
+	for _, s := range statuses {
+		if s.AppliedAt != nil {
+			versions = append(versions, s.String())
+		}
+	}
+	return strings.Join(versions, ",")
+}
+
+func TestCheckCurrentIsReadOnly(t *testing.T) {
+	m, db := newMigrator(t, testMigrations)
+	ctx := context.Background()
+
+	err := m.CheckCurrent(ctx)
+	if !errors.Is(err, dbmigrate.ErrPending) {
+		t.Fatalf("CheckCurrent = %v, want ErrPending", err)
+	}
+	if got := applied(t, m); got != "" {
+		t.Fatalf("applied = %q on a new database", got)
+	}
+	if got := tables(t, db); got != "" {
+		t.Fatalf("checking created tables %q", got)
+	}
+
+	if _, err := m.Up(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CheckCurrent(ctx); err != nil {
+		t.Fatalf("CheckCurrent after Up = %v", err)
+	}
+}
+
+func TestUpDown(t *testing.T) {
+	m, db := newMigrator(t, testMigrations)
+	ctx := context.Background()
+
+	done, err := m.Up(ctx, 1)
+	if err != nil || len(done) != 1 {
+		t.Fatalf("Up(1) = %v, %v", done, err)
+	}
+	if got := applied(t, m); got != "001_links" {
+		t.Fatalf("applied = %q after Up(1)", got)
+	}
+
+	if _, err := m.Up(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := applied(t, m); got != "001_links,002_tags" {
+		t.Fatalf("applied = %q after Up", got)
+	}
+	if got := tables(t, db); got != "links,schema_revisions,tags" {
+		t.Fatalf("tables = %q after Up", got)
+	}
+	if done, err := m.Up(ctx, 0); err != nil || len(done) != 0 {
+		t.Fatalf("second Up = %v, %v", done, err)
+	}
+
+	done, err = m.Down(ctx, 1)
+	if err != nil || len(done) != 1 || done[0].Version != "002" {
+		t.Fatalf("Down(1) = %v, %v", done, err)
+	}
+	if got := applied(t, m); got != "001_links" {
+		t.Fatalf("applied = %q after Down(1)", got)
+	}
+	if got := tables(t, db); got != "links,schema_revisions" {
+		t.Fatalf("tables = %q after Down(1)", got)
+	}
+	if err := m.CheckCurrent(ctx); !errors.Is(err, dbmigrate.ErrPending) {
+		t.Fatalf("CheckCurrent after Down = %v, want ErrPending", err)
+	}
+}
+
+func TestFailedMigrationLeavesNoTrace(t *testing.T) {
+	migrations := fstest.MapFS{
+		"001_links.up.sql": {Data: []byte("CREATE TABLE links (id INTEGER PRIMARY KEY);")},
+		"002_bad.up.sql":   {Data: []byte("CREATE TABLE tags (id INTEGER PRIMARY KEY); NOT SQL;")},
+	}
+	m, db := newMigrator(t, migrations)
+	ctx := context.Background()
+
+	done, err := m.Up(ctx, 0)
+	if err == nil || len(done) != 1 {
+		t.Fatalf("Up = %v, %v, want the second migration to fail", done, err)
+	}
+	if got := applied(t, m); got != "001_links" {
+		t.Fatalf("applied = %q", got)
+	}
+	if got := tables(t, db); got != "links,schema_revisions" {
+		t.Fatalf("tables = %q", got)
+	}
+
+	// Without a down migration the first one can't be reverted
+	if _, err := m.Down(ctx, 1); err == nil {
+		t.Fatal("Down succeeded without a down migration")
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	m, db := newMigrator(t, testMigrations)
+	ctx := context.Background()
+
+	if _, err := m.Baseline(ctx, "999"); err == nil {
+		t.Fatal("Baseline accepted an unknown version")
+	}
+	done, err := m.Baseline(ctx, "001")
+	if err != nil || len(done) != 1 {
+		t.Fatalf("Baseline = %v, %v", done, err)
+	}
+	if got := applied(t, m); got != "001_links" {
+		t.Fatalf("applied = %q", got)
+	}
+	// Baselined migrations are recorded, not run
+	if got := tables(t, db); got != "schema_revisions" {
+		t.Fatalf("tables = %q", got)
+	}
+}
@@ -1,25 +1,61 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"strings"
+	"time"
 
 	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/refreshtoken"
 	"bookmark-shortener/ent/user"
+	"bookmark-shortener/internal/background"
+	"bookmark-shortener/internal/lockout"
+	"bookmark-shortener/internal/logging"
 	"bookmark-shortener/internal/models"
+	"bookmark-shortener/internal/session"
 	"bookmark-shortener/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
-	client *ent.Client
-	secret []byte
+	client   *ent.Client
+	secret   []byte
+	sessions session.Store
+	verifier *VerificationHandler
+	mfa      *MFAHandler
+	tasks    *background.Group
+
+	// Failed logins are counted on the user for known emails, and in
+	// memory for unknown emails and per client IP
+	accountLockout lockout.Policy
+	emailFailures  *lockout.Tracker
+	ipFailures     *lockout.Tracker
 }
 
-func NewAuthHandler(client *ent.Client, secret string) *AuthHandler {
+// NewAuthHandler issues JWT access/refresh token pairs, or opaque session IDs
+// when a session store is given. New users are mailed a verification link
+// by verifier, and users with two-factor authentication are challenged by
+// mfa. Repeated failed logins are throttled per account with
+// accountLockout and per client IP with ipLockout; failures of existing
+// accounts are recorded in tasks.
+func NewAuthHandler(client *ent.Client, secret string, sessions session.Store, verifier *VerificationHandler, mfa *MFAHandler, tasks *background.Group, accountLockout, ipLockout lockout.Policy) *AuthHandler {
+	// Hash the stand-in password now rather than during the first login
+	// for an unknown email, which would take twice as long
+	utils.PrecomputeDummyHash()
+
 	return &AuthHandler{
-		client: client,
-		secret: []byte(secret),
+		client:         client,
+		secret:         []byte(secret),
+		sessions:       sessions,
+		verifier:       verifier,
+		mfa:            mfa,
+		tasks:          tasks,
+		accountLockout: accountLockout,
+		emailFailures:  lockout.NewTracker(accountLockout),
+		ipFailures:     lockout.NewTracker(ipLockout),
 	}
 }
 
@@ -31,7 +67,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 
 	// Check if user exists
-	exists, err := h.client.User.Query().Where(user.Email(req.Email)).Exist(c)
+	exists, err := h.client.User.Query().Where(user.Email(req.Email)).Exist(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
@@ -52,12 +88,17 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	u, err := h.client.User.Create().
 		SetEmail(req.Email).
 		SetPasswordHash(hashedPassword).
-		Save(c)
+		Save(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
 		return
 	}
 
+	// The account works without it; the user can ask for another link
+	if err := h.verifier.send(c, u); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to send verification email", "error", err, "user_id", u.ID)
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"id":    u.ID,
 		"email": u.Email,
@@ -71,28 +112,302 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	clientIP := c.ClientIP()
+	if wait := h.ipFailures.RetryAfter(clientIP); wait > 0 {
+		tooManyLoginAttempts(c, wait)
+		return
+	}
+
 	// Find user
-	u, err := h.client.User.Query().Where(user.Email(req.Email)).Only(c)
-	if err != nil {
+	u, err := h.client.User.Query().Where(user.Email(req.Email)).Only(c.Request.Context())
+	if err != nil && !ent.IsNotFound(err) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	// Unknown emails are throttled like accounts and take as long to
+	// reject, so neither reveals which accounts exist
+	if u == nil {
+		email := strings.ToLower(req.Email)
+		if wait := h.emailFailures.RetryAfter(email); wait > 0 {
+			tooManyLoginAttempts(c, wait)
+			return
+		}
+		utils.CheckPasswordWithoutUser(req.Password)
+		h.emailFailures.Fail(email)
+		h.ipFailures.Fail(clientIP)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	// Check password
+	now := time.Now()
+	if until := h.accountRetryAt(u, now); now.Before(until) {
+		tooManyLoginAttempts(c, until.Sub(now))
+		return
+	}
+
+	// Check password. The failure is written after responding, so a wrong
+	// password costs the same bcrypt comparison and map updates as an
+	// unknown email
 	if err := utils.CheckPassword(u.PasswordHash, req.Password); err != nil {
+		h.tasks.Go(c.Request.Context(), func(ctx context.Context) {
+			h.recordFailedLogin(ctx, u, now)
+		})
+		h.ipFailures.Fail(clientIP)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	// Generate JWT
-	tokenString, err := utils.GenerateToken(u.ID.String(), h.secret)
+	// Failures are only reset once the second factor passes too, so
+	// guessing codes counts towards the lockout
+	if u.TotpEnabledAt != nil {
+		challenge, err := h.mfa.challenge(u)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+			return
+		}
+		c.JSON(http.StatusOK, challenge)
+		return
+	}
+
+	h.resetFailedLogins(c, u)
+	h.completeLogin(c, u)
+}
+
+// VerifyMFA finishes a login challenged for a second factor, with a TOTP
+// code or a recovery code. Wrong codes count as failed logins.
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req models.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clientIP := c.ClientIP()
+	if wait := h.ipFailures.RetryAfter(clientIP); wait > 0 {
+		tooManyLoginAttempts(c, wait)
+		return
+	}
+
+	subject, err := utils.ValidateMFAToken(req.MFAToken, h.secret)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired MFA token"})
+		return
+	}
+	userID, err := uuid.Parse(subject)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired MFA token"})
+		return
+	}
+	u, err := h.client.User.Query().Where(user.ID(userID), user.TotpEnabledAtNotNil()).Only(c.Request.Context())
+	if err != nil {
+		if ent.IsNotFound(err) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired MFA token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	now := time.Now()
+	if until := h.accountRetryAt(u, now); now.Before(until) {
+		tooManyLoginAttempts(c, until.Sub(now))
+		return
+	}
+
+	ok, err := h.mfa.checkCode(c.Request.Context(), u, req.Code, req.RecoveryCode, now)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to check second factor", "error", err, "user_id", u.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check code"})
+		return
+	}
+	if !ok {
+		h.recordFailedLogin(c.Request.Context(), u, now)
+		h.ipFailures.Fail(clientIP)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code"})
+		return
+	}
+	h.resetFailedLogins(c, u)
+	h.completeLogin(c, u)
+}
+
+// completeLogin responds with a new session or token pair for u.
+func (h *AuthHandler) completeLogin(c *gin.Context, u *ent.User) {
+	if h.sessions != nil {
+		sessionID, err := h.sessions.Create(c.Request.Context(), u.ID.String())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"access_token": sessionID,
+			"token_type":   "bearer",
+		})
+		return
+	}
+
+	// Every login starts a new refresh token family
+	tokens, err := h.issueTokens(c.Request.Context(), h.client, u.ID, uuid.New())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"access_token": tokenString,
-		"token_type":   "bearer",
-	})
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	if h.sessions != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sessions are renewed on use and cannot be refreshed"})
+		return
+	}
+
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rt, err := h.client.RefreshToken.Query().
+		Where(refreshtoken.TokenHash(utils.HashToken(req.RefreshToken))).
+		WithUser().
+		Only(c.Request.Context())
+	if err != nil {
+		if ent.IsNotFound(err) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	if rt.RevokedAt != nil || time.Now().After(rt.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	if rt.UsedAt != nil {
+		h.handleReuse(c, rt)
+		return
+	}
+
+	tx, err := h.client.Tx(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	// Mark the token used only if nobody else did in the meantime, so two
+	// concurrent refreshes with the same token cannot both succeed
+	n, err := tx.RefreshToken.Update().
+		Where(
+			refreshtoken.ID(rt.ID),
+			refreshtoken.UsedAtIsNil(),
+			refreshtoken.RevokedAtIsNil(),
+		).
+		SetUsedAt(time.Now()).
+		Save(c.Request.Context())
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if n == 0 {
+		tx.Rollback()
+		h.handleReuse(c, rt)
+		return
+	}
+
+	tokens, err := h.issueTokens(c.Request.Context(), tx.Client(), rt.Edges.User.ID, rt.FamilyID)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if h.sessions != nil {
+		if err := h.sessions.Delete(c.Request.Context(), c.GetString("session_id")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete session"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+		return
+	}
+
+	familyID, err := uuid.Parse(c.GetString("token_family"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token family"})
+		return
+	}
+
+	if err := h.revokeFamily(c.Request.Context(), familyID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke tokens"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// handleReuse is called when an already rotated refresh token is presented
+// again. Either the client or an attacker holds a stolen copy, so the whole
+// family is revoked and both parties have to log in again.
+func (h *AuthHandler) handleReuse(c *gin.Context, rt *ent.RefreshToken) {
+	if err := h.revokeFamily(c.Request.Context(), rt.FamilyID); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to revoke token family", "error", err, "family_id", rt.FamilyID)
+	}
+	c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected"})
+}
+
+func (h *AuthHandler) revokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	return h.client.RefreshToken.Update().
+		Where(
+			refreshtoken.FamilyID(familyID),
+			refreshtoken.RevokedAtIsNil(),
+		).
+		SetRevokedAt(time.Now()).
+		Exec(ctx)
+}
+
+// issueTokens signs an access token and persists a fresh refresh token in
+// the given family. The access token's jti is stored alongside so that
+// revoking the family also revokes every access token issued from it.
+func (h *AuthHandler) issueTokens(ctx context.Context, client *ent.Client, userID, familyID uuid.UUID) (gin.H, error) {
+	accessToken, jti, err := utils.GenerateToken(userID.String(), familyID.String(), h.secret)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.RefreshToken.Create().
+		SetTokenHash(utils.HashToken(refreshToken)).
+		SetFamilyID(familyID).
+		SetAccessJti(jti).
+		SetExpiresAt(time.Now().Add(utils.RefreshTokenTTL)).
+		SetUserID(userID).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return gin.H{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"token_type":    "bearer",
+		"expires_in":    int(utils.AccessTokenTTL.Seconds()),
+	}, nil
 }
@@ -2,10 +2,14 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"bookmark-shortener/ent"
 	"bookmark-shortener/ent/bookmark"
 	"bookmark-shortener/ent/user"
+	"bookmark-shortener/internal/baseurl"
 	"bookmark-shortener/internal/models"
 	"bookmark-shortener/internal/utils"
 
@@ -13,12 +17,17 @@ import (
 	"github.com/google/uuid"
 )
 
+// shortCodeAttempts bounds how often Create draws a new generated code after
+// losing it to a concurrent create.
+const shortCodeAttempts = 3
+
 type BookmarkHandler struct {
-	client *ent.Client
+	client  *ent.Client
+	baseURL *baseurl.Resolver
 }
 
-func NewBookmarkHandler(client *ent.Client) *BookmarkHandler {
-	return &BookmarkHandler{client: client}
+func NewBookmarkHandler(client *ent.Client, baseURL *baseurl.Resolver) *BookmarkHandler {
+	return &BookmarkHandler{client: client, baseURL: baseURL}
 }
 
 func (h *BookmarkHandler) Create(c *gin.Context) {
@@ -29,9 +38,19 @@ func (h *BookmarkHandler) Create(c *gin.Context) {
 	}
 
 	userID := c.GetString("user_id")
-	shortCode := utils.GenerateShortCode()
 
-	existingBookmark, err := h.client.Bookmark.Query().Where(bookmark.URL(req.URL)).Exist(c)
+	if req.Alias != "" {
+		if err := utils.ValidateAlias(req.Alias); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+
+	existingBookmark, err := h.client.Bookmark.Query().Where(bookmark.URL(req.URL)).Exist(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
@@ -42,17 +61,23 @@ func (h *BookmarkHandler) Create(c *gin.Context) {
 		return
 	}
 
-	// Ensure unique short code
-	for {
-		exists, err := h.client.Bookmark.Query().Where(bookmark.ShortCode(shortCode)).Exist(c)
+	shortCode := req.Alias
+	if shortCode != "" {
+		taken, err := h.client.Bookmark.Query().Where(bookmark.ShortCode(shortCode)).Exist(c.Request.Context())
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 			return
 		}
-		if !exists {
-			break
+		if taken {
+			c.JSON(http.StatusConflict, gin.H{"error": "Alias already taken"})
+			return
+		}
+	} else {
+		shortCode = h.generateUniqueShortCode(c)
+		if shortCode == "" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
 		}
-		shortCode = utils.GenerateShortCode()
 	}
 
 	ownerUUID, err := uuid.Parse(userID)
@@ -61,26 +86,41 @@ func (h *BookmarkHandler) Create(c *gin.Context) {
 		return
 	}
 
-	b, err := h.client.Bookmark.Create().
-		SetTitle(req.Title).
-		SetURL(req.URL).
-		SetShortCode(shortCode).
-		SetOwnerID(ownerUUID).
-		Save(c)
+	var b *ent.Bookmark
+	for attempt := 1; ; attempt++ {
+		b, err = h.client.Bookmark.Create().
+			SetTitle(req.Title).
+			SetURL(req.URL).
+			SetShortCode(shortCode).
+			SetNillableExpiresAt(req.ExpiresAt).
+			SetNillableMaxVisits(req.MaxVisits).
+			SetOwnerID(ownerUUID).
+			Save(c.Request.Context())
+		// A generated code can still be taken by a concurrent create, so
+		// draw another one instead of failing
+		if err == nil || req.Alias != "" || !isShortCodeConflict(err) || attempt == shortCodeAttempts {
+			break
+		}
+		if shortCode = h.generateUniqueShortCode(c); shortCode == "" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create bookmark"})
+		switch {
+		case req.Alias != "" && isShortCodeConflict(err):
+			// Lost a race against a concurrent create with the same alias
+			c.JSON(http.StatusConflict, gin.H{"error": "Alias already taken"})
+		case ent.IsConstraintError(err) && !isShortCodeConflict(err):
+			c.JSON(http.StatusConflict, gin.H{"error": "Bookmark already exists"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create bookmark"})
+		}
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"id":          b.ID,
-		"title":       b.Title,
-		"url":         b.URL,
-		"short_code":  b.ShortCode,
-		"short_url":   getBaseURL(c) + "/" + b.ShortCode,
-		"visit_count": b.VisitCount,
-		"created_at":  b.CreatedAt,
-	})
+	c.Header("ETag", bookmarkETag(b))
+	c.JSON(http.StatusCreated, bookmarkResponse(b, h.baseURL.Resolve(c.Request)))
 }
 
 func (h *BookmarkHandler) GetAll(c *gin.Context) {
@@ -92,26 +132,65 @@ func (h *BookmarkHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	bookmarks, err := h.client.Bookmark.Query().
-		Where(bookmark.HasOwnerWith(user.ID(ownerUUID))).
-		All(c)
+	var req models.ListBookmarksQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultPageSize
+	}
+	if req.Sort == "" {
+		req.Sort = sortCreatedAt
+	}
+	desc := req.Order != "asc"
+
+	query := h.client.Bookmark.Query().
+		Where(bookmark.HasOwnerWith(user.ID(ownerUUID)))
+	if req.Query != "" {
+		query.Where(bookmark.TitleContainsFold(req.Query))
+	}
+	if req.Domain != "" {
+		query.Where(domainPredicate(req.Domain))
+	}
+	if !req.CreatedAfter.IsZero() {
+		query.Where(bookmark.CreatedAtGTE(req.CreatedAfter))
+	}
+	if !req.CreatedBefore.IsZero() {
+		query.Where(bookmark.CreatedAtLT(req.CreatedBefore))
+	}
+	if req.Cursor != "" {
+		cursor, err := decodePageCursor(req.Cursor, req.Sort, desc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		query.Where(cursor.after())
+	}
+
+	// Fetch one extra row to find out whether there is a next page
+	bookmarks, err := query.
+		Order(bookmarkOrder(req.Sort, desc)...).
+		Limit(req.Limit + 1).
+		All(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bookmarks"})
 		return
 	}
 
+	// The body stays a bare array like the other implementations, so the
+	// next page is only announced in headers
+	baseURL := h.baseURL.Resolve(c.Request)
+	if len(bookmarks) > req.Limit {
+		bookmarks = bookmarks[:req.Limit]
+		cursor := newPageCursor(req.Sort, desc, bookmarks[len(bookmarks)-1]).encode()
+		c.Header("Link", "<"+nextPageLink(baseURL, c.Request.URL, cursor)+`>; rel="next"`)
+		c.Header("X-Next-Cursor", cursor)
+	}
+
 	result := make([]gin.H, len(bookmarks))
-	baseURL := getBaseURL(c)
 	for i, b := range bookmarks {
-		result[i] = gin.H{
-			"id":          b.ID,
-			"title":       b.Title,
-			"url":         b.URL,
-			"short_code":  b.ShortCode,
-			"short_url":   baseURL + "/" + b.ShortCode,
-			"visit_count": b.VisitCount,
-			"created_at":  b.CreatedAt,
-		}
+		result[i] = bookmarkResponse(b, baseURL)
 	}
 
 	c.JSON(http.StatusOK, result)
@@ -136,7 +215,7 @@ func (h *BookmarkHandler) GetByID(c *gin.Context) {
 			bookmark.ID(bookmarkUUID),
 			bookmark.HasOwnerWith(user.ID(ownerUUID)),
 		).
-		Only(c)
+		Only(c.Request.Context())
 	if err != nil {
 		if ent.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark not found"})
@@ -146,15 +225,129 @@ func (h *BookmarkHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"id":          b.ID,
-		"title":       b.Title,
-		"url":         b.URL,
-		"short_code":  b.ShortCode,
-		"short_url":   getBaseURL(c) + "/" + b.ShortCode,
-		"visit_count": b.VisitCount,
-		"created_at":  b.CreatedAt,
-	})
+	c.Header("ETag", bookmarkETag(b))
+	c.JSON(http.StatusOK, bookmarkResponse(b, h.baseURL.Resolve(c.Request)))
+}
+
+func (h *BookmarkHandler) Update(c *gin.Context) {
+	userID := c.GetString("user_id")
+	ownerUUID, err := uuid.Parse(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	bookmarkID := c.Param("id")
+	bookmarkUUID, err := uuid.Parse(bookmarkID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bookmark ID"})
+		return
+	}
+
+	var req models.UpdateBookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Title == nil && req.URL == nil && !req.ExpiresAt.Set && !req.MaxVisits.Set {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+	if expiresAt := req.ExpiresAt.Value; expiresAt != nil && !expiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+	if maxVisits := req.MaxVisits.Value; maxVisits != nil && *maxVisits < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_visits must be at least 1"})
+		return
+	}
+
+	b, err := h.client.Bookmark.Query().
+		Where(
+			bookmark.ID(bookmarkUUID),
+			bookmark.HasOwnerWith(user.ID(ownerUUID)),
+		).
+		Only(c.Request.Context())
+	if err != nil {
+		if ent.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch != "" && !etagMatches(ifMatch, bookmarkETag(b)) {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Bookmark has been modified"})
+		return
+	}
+
+	if req.URL != nil && *req.URL != b.URL {
+		exists, err := h.client.Bookmark.Query().
+			Where(
+				bookmark.URL(*req.URL),
+				bookmark.IDNEQ(b.ID),
+			).
+			Exist(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		if exists {
+			c.JSON(http.StatusConflict, gin.H{"error": "Bookmark already exists"})
+			return
+		}
+	}
+
+	update := h.client.Bookmark.Update().Where(bookmark.ID(b.ID))
+	if ifMatch != "" {
+		// Only apply the update if nobody changed the bookmark since it was
+		// read. The range tolerates databases storing sub-microsecond digits.
+		version := b.UpdatedAt.Truncate(time.Microsecond)
+		update.Where(
+			bookmark.UpdatedAtGTE(version),
+			bookmark.UpdatedAtLT(version.Add(time.Microsecond)),
+		)
+	}
+	if req.Title != nil {
+		update.SetTitle(*req.Title)
+	}
+	if req.URL != nil {
+		update.SetURL(*req.URL)
+	}
+	if req.ExpiresAt.Set {
+		if req.ExpiresAt.Value != nil {
+			update.SetExpiresAt(*req.ExpiresAt.Value)
+		} else {
+			update.ClearExpiresAt()
+		}
+	}
+	if req.MaxVisits.Set {
+		if req.MaxVisits.Value != nil {
+			update.SetMaxVisits(*req.MaxVisits.Value)
+		} else {
+			update.ClearMaxVisits()
+		}
+	}
+
+	n, err := update.Save(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update bookmark"})
+		return
+	}
+	if n == 0 {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Bookmark has been modified"})
+		return
+	}
+
+	b, err = h.client.Bookmark.Get(c.Request.Context(), b.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.Header("ETag", bookmarkETag(b))
+	c.JSON(http.StatusOK, bookmarkResponse(b, h.baseURL.Resolve(c.Request)))
 }
 
 func (h *BookmarkHandler) Delete(c *gin.Context) {
@@ -173,7 +366,7 @@ func (h *BookmarkHandler) Delete(c *gin.Context) {
 
 	var err = h.client.Bookmark.DeleteOneID(bookmarkUUID).
 		Where(bookmark.HasOwnerWith(user.ID(ownerUUID))).
-		Exec(c)
+		Exec(c.Request.Context())
 	if err != nil {
 		if ent.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark not found"})
@@ -186,10 +379,55 @@ func (h *BookmarkHandler) Delete(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Bookmark deleted successfully"})
 }
 
-func getBaseURL(c *gin.Context) string {
-	scheme := "http"
-	if c.Request.TLS != nil {
-		scheme = "https"
+// generateUniqueShortCode returns a random short code not yet in use, or ""
+// if the database could not be queried.
+func (h *BookmarkHandler) generateUniqueShortCode(c *gin.Context) string {
+	for {
+		shortCode := utils.GenerateShortCode()
+		exists, err := h.client.Bookmark.Query().Where(bookmark.ShortCode(shortCode)).Exist(c.Request.Context())
+		if err != nil {
+			return ""
+		}
+		if !exists {
+			return shortCode
+		}
+	}
+}
+
+// isShortCodeConflict reports whether err violates the unique index on
+// short_code. Both SQLite and Postgres name the column or index in the
+// message.
+func isShortCodeConflict(err error) bool {
+	return ent.IsConstraintError(err) && strings.Contains(err.Error(), bookmark.FieldShortCode)
+}
+
+func bookmarkResponse(b *ent.Bookmark, baseURL string) gin.H {
+	return gin.H{
+		"id":          b.ID,
+		"title":       b.Title,
+		"url":         b.URL,
+		"short_code":  b.ShortCode,
+		"short_url":   baseURL + "/" + b.ShortCode,
+		"visit_count": b.VisitCount,
+		"expires_at":  b.ExpiresAt,
+		"max_visits":  b.MaxVisits,
+		"created_at":  b.CreatedAt,
+	}
+}
+
+// bookmarkETag derives a version tag from updated_at. Microsecond precision
+// is the finest that survives a round trip through Postgres.
+func bookmarkETag(b *ent.Bookmark) string {
+	return `"` + strconv.FormatInt(b.UpdatedAt.UnixMicro(), 36) + `"`
+}
+
+// etagMatches implements the strong comparison used by If-Match.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
 	}
-	return scheme + "://" + c.Request.Host
+	return false
 }
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"bookmark-shortener/ent"
+
+	"github.com/gin-gonic/gin"
+)
+
+const readinessTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	client   *ent.Client
+	draining atomic.Bool
+}
+
+func NewHealthHandler(client *ent.Client) *HealthHandler {
+	return &HealthHandler{client: client}
+}
+
+// Live reports that the process is up. It deliberately checks nothing else,
+// so a database outage doesn't get every instance restarted.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready reports whether this instance should receive traffic: it is not
+// shutting down and can reach the database.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	rows, err := h.client.QueryContext(ctx, "SELECT 1")
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": "Database unreachable"})
+		return
+	}
+	rows.Close()
+
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
+
+// Drain makes Ready fail from now on, so load balancers stop routing new
+// requests here while in-flight ones finish.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/recoverycode"
+	"bookmark-shortener/ent/user"
+	"bookmark-shortener/internal/mfa"
+	"bookmark-shortener/internal/models"
+	"bookmark-shortener/internal/secretbox"
+	"bookmark-shortener/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var errMFANotConfigured = errors.New("two-factor authentication is not configured")
+
+type MFAHandler struct {
+	client       *ent.Client
+	secret       []byte
+	box          *secretbox.Box
+	issuer       string
+	challengeTTL time.Duration
+}
+
+// NewMFAHandler enrolls users in TOTP two-factor authentication, with
+// secrets encrypted by box, and signs login challenges valid for
+// challengeTTL. Without a box, enrollment is unavailable.
+func NewMFAHandler(client *ent.Client, secret string, box *secretbox.Box, issuer string, challengeTTL time.Duration) *MFAHandler {
+	return &MFAHandler{
+		client:       client,
+		secret:       []byte(secret),
+		box:          box,
+		issuer:       issuer,
+		challengeTTL: challengeTTL,
+	}
+}
+
+// Setup generates a new TOTP secret for the authenticated user. It only
+// takes effect once a code from it is confirmed.
+func (h *MFAHandler) Setup(c *gin.Context) {
+	u, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if u.TotpEnabledAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+
+	enrollment, err := mfa.Enroll(h.issuer, u.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate secret"})
+		return
+	}
+	sealed, err := h.box.Seal([]byte(enrollment.Secret), u.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate secret"})
+		return
+	}
+	if err := h.client.User.UpdateOneID(u.ID).SetTotpSecret(sealed).Exec(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"secret":      enrollment.Secret,
+		"otpauth_uri": enrollment.URI,
+	})
+}
+
+// Confirm enables two-factor authentication with a code from the secret
+// returned by Setup, and returns the recovery codes. They are shown once.
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	u, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if u.TotpEnabledAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+	if u.TotpSecret == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Start two-factor setup first"})
+		return
+	}
+
+	secret, err := h.box.Open(*u.TotpSecret, u.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read secret"})
+		return
+	}
+	step, ok := mfa.Validate(string(secret), req.Code, time.Now(), u.TotpLastStep)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid code"})
+		return
+	}
+
+	codes, err := mfa.GenerateRecoveryCodes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate recovery codes"})
+		return
+	}
+
+	tx, err := h.client.Tx(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if err := enableTOTP(c.Request.Context(), tx, u, step, codes); err != nil {
+		tx.Rollback()
+		if ent.IsNotFound(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// enableTOTP turns on the pending secret of u and replaces its recovery
+// codes. It returns a not found error if a concurrent request enabled it
+// first.
+func enableTOTP(ctx context.Context, tx *ent.Tx, u *ent.User, step int64, codes []string) error {
+	n, err := tx.User.Update().
+		Where(user.ID(u.ID), user.TotpEnabledAtIsNil(), user.TotpSecret(*u.TotpSecret)).
+		SetTotpEnabledAt(time.Now()).
+		SetTotpLastStep(step).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &ent.NotFoundError{}
+	}
+
+	if _, err := tx.RecoveryCode.Delete().Where(recoverycode.HasUserWith(user.ID(u.ID))).Exec(ctx); err != nil {
+		return err
+	}
+	builders := make([]*ent.RecoveryCodeCreate, len(codes))
+	for i, code := range codes {
+		builders[i] = tx.RecoveryCode.Create().
+			SetCodeHash(utils.HashToken(mfa.NormalizeRecoveryCode(code))).
+			SetUserID(u.ID)
+	}
+	return tx.RecoveryCode.CreateBulk(builders...).Exec(ctx)
+}
+
+// currentUser loads the authenticated user, or responds with an error.
+func (h *MFAHandler) currentUser(c *gin.Context) (*ent.User, bool) {
+	if h.box == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Two-factor authentication is not configured"})
+		return nil, false
+	}
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		return nil, false
+	}
+	u, err := h.client.User.Get(c.Request.Context(), userID)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return nil, false
+	}
+	return u, true
+}
+
+// challenge returns the response asking u for a second factor.
+func (h *MFAHandler) challenge(u *ent.User) (gin.H, error) {
+	token, err := utils.GenerateMFAToken(u.ID.String(), h.challengeTTL, h.secret)
+	if err != nil {
+		return nil, err
+	}
+	return gin.H{
+		"mfa_required": true,
+		"mfa_token":    token,
+		"expires_in":   int(h.challengeTTL.Seconds()),
+	}, nil
+}
+
+// checkCode reports whether code, a TOTP code, or else recoveryCode is a
+// valid second factor for u, and uses it up.
+func (h *MFAHandler) checkCode(ctx context.Context, u *ent.User, code, recoveryCode string, now time.Time) (bool, error) {
+	if code == "" {
+		n, err := h.client.RecoveryCode.Update().
+			Where(
+				recoverycode.HasUserWith(user.ID(u.ID)),
+				recoverycode.CodeHash(utils.HashToken(mfa.NormalizeRecoveryCode(recoveryCode))),
+				recoverycode.UsedAtIsNil(),
+			).
+			SetUsedAt(now).
+			Save(ctx)
+		return n == 1, err
+	}
+
+	if h.box == nil || u.TotpSecret == nil {
+		return false, errMFANotConfigured
+	}
+	secret, err := h.box.Open(*u.TotpSecret, u.ID.String())
+	if err != nil {
+		return false, err
+	}
+	step, ok := mfa.Validate(string(secret), code, now, u.TotpLastStep)
+	if !ok {
+		return false, nil
+	}
+	// Only one request can move past a step, so a code isn't accepted
+	// twice by concurrent logins
+	n, err := h.client.User.Update().
+		Where(user.ID(u.ID), user.TotpLastStepLT(step)).
+		SetTotpLastStep(step).
+		Save(ctx)
+	return n == 1, err
+}
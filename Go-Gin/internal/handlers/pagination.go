@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/ent/predicate"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 50
+
+	sortCreatedAt  = "created_at"
+	sortVisitCount = "visit_count"
+	sortTitle      = "title"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor marks the last row of a page. It is keyed on the sort column
+// plus the ID as a tie breaker, so pages stay stable while rows are added.
+// It is only valid for the sort and order it was issued for.
+type pageCursor struct {
+	Sort       string    `json:"s"`
+	Desc       bool      `json:"d,omitempty"`
+	CreatedAt  time.Time `json:"c,omitempty"`
+	VisitCount int       `json:"v,omitempty"`
+	Title      string    `json:"t,omitempty"`
+	ID         uuid.UUID `json:"id"`
+}
+
+func newPageCursor(sortField string, desc bool, b *ent.Bookmark) pageCursor {
+	return pageCursor{
+		Sort:       sortField,
+		Desc:       desc,
+		CreatedAt:  b.CreatedAt,
+		VisitCount: b.VisitCount,
+		Title:      b.Title,
+		ID:         b.ID,
+	}
+}
+
+func (pc pageCursor) encode() string {
+	raw, _ := json.Marshal(pc)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodePageCursor(s, sortField string, desc bool) (pageCursor, error) {
+	var pc pageCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return pc, errInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &pc); err != nil || pc.Sort != sortField || pc.Desc != desc {
+		return pc, errInvalidCursor
+	}
+	return pc, nil
+}
+
+// after returns the keyset predicate selecting rows that come after the
+// cursor in its order.
+func (pc pageCursor) after() predicate.Bookmark {
+	idAfter := bookmark.IDGT(pc.ID)
+	if pc.Desc {
+		idAfter = bookmark.IDLT(pc.ID)
+	}
+
+	switch pc.Sort {
+	case sortVisitCount:
+		if pc.Desc {
+			return bookmark.Or(bookmark.VisitCountLT(pc.VisitCount), bookmark.And(bookmark.VisitCount(pc.VisitCount), idAfter))
+		}
+		return bookmark.Or(bookmark.VisitCountGT(pc.VisitCount), bookmark.And(bookmark.VisitCount(pc.VisitCount), idAfter))
+	case sortTitle:
+		if pc.Desc {
+			return bookmark.Or(bookmark.TitleLT(pc.Title), bookmark.And(bookmark.Title(pc.Title), idAfter))
+		}
+		return bookmark.Or(bookmark.TitleGT(pc.Title), bookmark.And(bookmark.Title(pc.Title), idAfter))
+	default:
+		if pc.Desc {
+			return bookmark.Or(bookmark.CreatedAtLT(pc.CreatedAt), bookmark.And(bookmark.CreatedAt(pc.CreatedAt), idAfter))
+		}
+		return bookmark.Or(bookmark.CreatedAtGT(pc.CreatedAt), bookmark.And(bookmark.CreatedAt(pc.CreatedAt), idAfter))
+	}
+}
+
+func bookmarkOrder(sortField string, desc bool) []bookmark.OrderOption {
+	direction := sql.OrderAsc()
+	if desc {
+		direction = sql.OrderDesc()
+	}
+
+	switch sortField {
+	case sortVisitCount:
+		return []bookmark.OrderOption{bookmark.ByVisitCount(direction), bookmark.ByID(direction)}
+	case sortTitle:
+		return []bookmark.OrderOption{bookmark.ByTitle(direction), bookmark.ByID(direction)}
+	default:
+		return []bookmark.OrderOption{bookmark.ByCreatedAt(direction), bookmark.ByID(direction)}
+	}
+}
+
+// domainPredicate matches bookmarks whose URL host is exactly domain, with
+// or without a port, over http or https. Hosts are case-insensitive.
+func domainPredicate(domain string) predicate.Bookmark {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	var preds []predicate.Bookmark
+	for _, scheme := range []string{"http://", "https://"} {
+		prefix := scheme + domain
+		preds = append(preds, bookmark.URLEqualFold(prefix))
+		for _, sep := range []string{"/", ":", "?", "#"} {
+			preds = append(preds, predicate.Bookmark(sql.FieldHasPrefixFold(bookmark.FieldURL, prefix+sep)))
+		}
+	}
+	return bookmark.Or(preds...)
+}
+
+// nextPageLink rewrites the current request URL to point at the next page.
+func nextPageLink(baseURL string, current *url.URL, cursor string) string {
+	query := current.Query()
+	query.Set("cursor", cursor)
+	return baseURL + current.Path + "?" + query.Encode()
+}
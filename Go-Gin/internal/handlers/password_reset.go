@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/passwordresettoken"
+	"bookmark-shortener/ent/refreshtoken"
+	"bookmark-shortener/ent/user"
+	"bookmark-shortener/internal/background"
+	"bookmark-shortener/internal/logging"
+	"bookmark-shortener/internal/mail"
+	"bookmark-shortener/internal/models"
+	"bookmark-shortener/internal/session"
+	"bookmark-shortener/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// resetResendInterval is how long a fresh reset token blocks sending
+// another, so the endpoint can't be used to flood a mailbox.
+const resetResendInterval = time.Minute
+
+type PasswordResetHandler struct {
+	client   *ent.Client
+	outbox   *mail.Outbox
+	tasks    *background.Group
+	sessions session.Store
+	ttl      time.Duration
+	resetURL string
+
+	// sending holds the addresses a reset is being sent to, so concurrent
+	// requests for one account can't both pass the resend check
+	mu      sync.Mutex
+	sending map[string]struct{}
+}
+
+// NewPasswordResetHandler mails reset tokens valid for ttl, looking up the
+// account in tasks after responding. Links point at resetURL, or the email
+// carries the bare token when it is empty. Resetting a password ends the
+// user's sessions in sessions, if given, and revokes their refresh tokens.
+func NewPasswordResetHandler(client *ent.Client, outbox *mail.Outbox, tasks *background.Group, sessions session.Store, ttl time.Duration, resetURL string) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		client:   client,
+		outbox:   outbox,
+		tasks:    tasks,
+		sessions: sessions,
+		ttl:      ttl,
+		resetURL: resetURL,
+		sending:  make(map[string]struct{}),
+	}
+}
+
+// Forgot mails a reset token to the account's address. It answers before
+// even looking the account up, so neither the answer nor its timing tells
+// whether the account exists.
+func (h *PasswordResetHandler) Forgot(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.tasks.Go(c.Request.Context(), func(ctx context.Context) {
+		if err := h.sendReset(ctx, req.Email); err != nil {
+			logging.FromContext(ctx).Error("Failed to send password reset", "error", err)
+		}
+	})
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the account exists, a reset email has been sent"})
+}
+
+// sendReset mails a new reset token to the account with email, unless there
+// is no such account, it was sent one moments ago or one is being sent.
+func (h *PasswordResetHandler) sendReset(ctx context.Context, email string) error {
+	if !h.startSending(email) {
+		return nil
+	}
+	defer h.doneSending(email)
+
+	u, err := h.client.User.Query().Where(user.Email(email)).Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	recent, err := h.client.PasswordResetToken.Query().
+		Where(
+			passwordresettoken.HasUserWith(user.ID(u.ID)),
+			passwordresettoken.UsedAtIsNil(),
+			passwordresettoken.CreatedAtGT(now.Add(-resetResendInterval)),
+		).
+		Exist(ctx)
+	if err != nil || recent {
+		return err
+	}
+
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	_, err = h.client.PasswordResetToken.Create().
+		SetTokenHash(utils.HashToken(token)).
+		SetExpiresAt(now.Add(h.ttl)).
+		SetUserID(u.ID).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+
+	h.outbox.Send(ctx, h.resetMessage(u.Email, token), "user_id", u.ID)
+	return nil
+}
+
+// startSending claims email, reporting false if a reset is already being
+// sent to it.
+func (h *PasswordResetHandler) startSending(email string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.sending[email]; ok {
+		return false
+	}
+	h.sending[email] = struct{}{}
+	return true
+}
+
+func (h *PasswordResetHandler) doneSending(email string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.sending, email)
+}
+
+// Reset sets a new password with a token from Forgot. Tokens work once, and
+// every other session and refresh token of the user stops working.
+func (h *PasswordResetHandler) Reset(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	tx, err := h.client.Tx(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	userID, err := resetPassword(c.Request.Context(), tx, utils.HashToken(req.Token), hashedPassword)
+	if err != nil {
+		tx.Rollback()
+		if ent.IsNotFound(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired reset token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	if h.sessions != nil {
+		if err := h.sessions.DeleteUser(c.Request.Context(), userID.String()); err != nil {
+			logging.FromContext(c.Request.Context()).Error("Failed to end sessions after password reset", "error", err, "user_id", userID)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset"})
+}
+
+// resetPassword consumes the reset token with tokenHash and sets the user's
+// password. It returns a not found error when the token is unknown, used or
+// expired.
+func resetPassword(ctx context.Context, tx *ent.Tx, tokenHash, passwordHash string) (uuid.UUID, error) {
+	now := time.Now()
+	rt, err := tx.PasswordResetToken.Query().
+		Where(
+			passwordresettoken.TokenHash(tokenHash),
+			passwordresettoken.UsedAtIsNil(),
+			passwordresettoken.ExpiresAtGT(now),
+		).
+		WithUser().
+		Only(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	u := rt.Edges.User
+
+	// Mark the token used only if nobody else did in the meantime, so two
+	// concurrent resets with the same token cannot both succeed
+	n, err := tx.PasswordResetToken.Update().
+		Where(passwordresettoken.ID(rt.ID), passwordresettoken.UsedAtIsNil()).
+		SetUsedAt(now).
+		Save(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if n == 0 {
+		return uuid.Nil, &ent.NotFoundError{}
+	}
+
+	// Proving control of the mailbox also lifts a lockout
+	err = tx.User.UpdateOneID(u.ID).
+		SetPasswordHash(passwordHash).
+		SetFailedLoginAttempts(0).
+		ClearLastFailedLoginAt().
+		ClearLockedUntil().
+		Exec(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	// Other outstanding reset tokens were sent for the old password
+	err = tx.PasswordResetToken.Update().
+		Where(passwordresettoken.HasUserWith(user.ID(u.ID)), passwordresettoken.UsedAtIsNil()).
+		SetUsedAt(now).
+		Exec(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	// Revoking refresh tokens also revokes the access tokens issued with them
+	err = tx.RefreshToken.Update().
+		Where(refreshtoken.HasUserWith(user.ID(u.ID)), refreshtoken.RevokedAtIsNil()).
+		SetRevokedAt(now).
+		Exec(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return u.ID, nil
+}
+
+func (h *PasswordResetHandler) resetMessage(to, token string) mail.Message {
+	instructions := fmt.Sprintf("Reset it with this token:\n\n    %s\n", token)
+	if h.resetURL != "" {
+		instructions = fmt.Sprintf("Choose a new one here:\n\n    %s\n", resetLink(h.resetURL, token))
+	}
+	return mail.Message{
+		To:      to,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Someone asked to reset the password of your account. %s\n"+
+			"This expires in %d minutes. If you didn't ask for it, ignore this email and your password stays the same.\n",
+			instructions, int(h.ttl.Minutes())),
+	}
+}
+
+// resetLink appends token to resetURL as a query parameter.
+func resetLink(resetURL, token string) string {
+	u, err := url.Parse(resetURL)
+	if err != nil {
+		return resetURL
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
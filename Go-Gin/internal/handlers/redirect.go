@@ -1,31 +1,46 @@
 package handlers
 
 import (
-	"log"
+	"errors"
 	"net/http"
+	"time"
 
 	"bookmark-shortener/ent"
 	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/ent/predicate"
+	"bookmark-shortener/internal/analytics"
+	"bookmark-shortener/internal/cache"
+	"bookmark-shortener/internal/logging"
+	"bookmark-shortener/internal/metrics"
+	"bookmark-shortener/internal/visits"
 
+	"entgo.io/ent/dialect/sql"
 	"github.com/gin-gonic/gin"
 )
 
 type RedirectHandler struct {
-	client *ent.Client
+	client   *ent.Client
+	cache    *cache.ShortCodeCache
+	recorder *analytics.Recorder
+	counter  *visits.Counter
 }
 
-func NewRedirectHandler(client *ent.Client) *RedirectHandler {
-	return &RedirectHandler{client: client}
+func NewRedirectHandler(client *ent.Client, shortCodes *cache.ShortCodeCache, recorder *analytics.Recorder, counter *visits.Counter) *RedirectHandler {
+	return &RedirectHandler{
+		client:   client,
+		cache:    shortCodes,
+		recorder: recorder,
+		counter:  counter,
+	}
 }
 
 func (h *RedirectHandler) Redirect(c *gin.Context) {
 	shortCode := c.Param("code")
 
-	b, err := h.client.Bookmark.Query().
-		Where(bookmark.ShortCode(shortCode)).
-		Only(c)
+	b, err := h.cache.Get(c.Request.Context(), shortCode)
 	if err != nil {
-		if ent.IsNotFound(err) {
+		if errors.Is(err, cache.ErrNotFound) {
+			metrics.ObserveRedirect(metrics.RedirectNotFound)
 			c.JSON(http.StatusNotFound, gin.H{"error": "Short URL not found"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
@@ -33,13 +48,68 @@ func (h *RedirectHandler) Redirect(c *gin.Context) {
 		return
 	}
 
-	// Increment visit count
-	_, err = h.client.Bookmark.UpdateOneID(b.ID).
-		SetVisitCount(b.VisitCount + 1).
-		Save(c)
-	if err != nil {
-		log.Printf("Failed to update visit count: %v", err)
+	now := time.Now()
+	// The cached visit_count may lag behind, so this only catches links that
+	// are certainly used up; the update below has the final say
+	if !isLive(b, now) {
+		metrics.ObserveRedirect(metrics.RedirectExpired)
+		c.JSON(http.StatusGone, gin.H{"error": "Short URL has expired"})
+		return
+	}
+
+	if b.MaxVisits != nil {
+		// Links with a visit limit are counted synchronously, re-checking the
+		// limits in the same statement so concurrent clicks cannot push a
+		// link past max_visits
+		n, err := h.client.Bookmark.Update().
+			Where(bookmark.ID(b.ID), live(now)).
+			AddVisitCount(1).
+			Save(c.Request.Context())
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("Failed to update visit count",
+				"error", err,
+				"bookmark_id", b.ID,
+				"short_code", shortCode,
+				"visit_count", b.VisitCount,
+				"max_visits", *b.MaxVisits,
+			)
+			// Redirecting uncounted would let the link exceed max_visits
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Could not record the visit, try again"})
+			return
+		}
+		if n == 0 {
+			metrics.ObserveRedirect(metrics.RedirectExpired)
+			c.JSON(http.StatusGone, gin.H{"error": "Short URL has expired"})
+			return
+		}
+	} else {
+		h.counter.Add(b.ID)
 	}
 
+	h.counter.AddClick(h.recorder.NewClick(b.ID, c.ClientIP(), c.Request.UserAgent(), c.Request.Referer()))
+
+	metrics.ObserveRedirect(metrics.RedirectFound)
 	c.Redirect(http.StatusFound, b.URL)
 }
+
+// isLive reports whether b has neither expired nor used up its visits.
+func isLive(b *ent.Bookmark, now time.Time) bool {
+	if b.ExpiresAt != nil && !now.Before(*b.ExpiresAt) {
+		return false
+	}
+	if b.MaxVisits != nil && b.VisitCount >= *b.MaxVisits {
+		return false
+	}
+	return true
+}
+
+// live is the SQL counterpart of isLive.
+func live(now time.Time) predicate.Bookmark {
+	return bookmark.And(
+		bookmark.Or(bookmark.ExpiresAtIsNil(), bookmark.ExpiresAtGT(now)),
+		bookmark.Or(
+			bookmark.MaxVisitsIsNil(),
+			predicate.Bookmark(sql.FieldsLT(bookmark.FieldVisitCount, bookmark.FieldMaxVisits)),
+		),
+	)
+}
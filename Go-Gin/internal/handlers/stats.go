@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/ent/clickevent"
+	"bookmark-shortener/ent/predicate"
+	"bookmark-shortener/ent/user"
+	"bookmark-shortener/internal/models"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	maxStatsBuckets = 24 * 31
+	topReferrers    = 10
+)
+
+type statsBucket struct {
+	Start          time.Time `json:"start"`
+	Clicks         int       `json:"clicks"`
+	UniqueVisitors int       `json:"unique_visitors"`
+}
+
+type referrerCount struct {
+	Host   string `json:"host"`
+	Clicks int    `json:"clicks"`
+}
+
+func (h *BookmarkHandler) Stats(c *gin.Context) {
+	userID := c.GetString("user_id")
+	ownerUUID, err := uuid.Parse(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	bookmarkID := c.Param("id")
+	bookmarkUUID, err := uuid.Parse(bookmarkID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bookmark ID"})
+		return
+	}
+
+	var req models.StatsQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	step := 24 * time.Hour
+	if req.Bucket == "hour" {
+		step = time.Hour
+	} else {
+		req.Bucket = "day"
+	}
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+	if req.From.IsZero() {
+		req.From = req.To.Add(-30 * step)
+	}
+	from := req.From.UTC().Truncate(step)
+	to := req.To.UTC()
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+	if to.Sub(from)/step >= maxStatsBuckets {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Time range too large for bucket size"})
+		return
+	}
+
+	b, err := h.client.Bookmark.Query().
+		Where(
+			bookmark.ID(bookmarkUUID),
+			bookmark.HasOwnerWith(user.ID(ownerUUID)),
+		).
+		Only(c.Request.Context())
+	if err != nil {
+		if ent.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	inRange := []predicate.ClickEvent{
+		clickevent.OccurredAtGTE(from),
+		clickevent.OccurredAtLT(to),
+	}
+
+	var buckets []struct {
+		Bucket         int64 `sql:"bucket"`
+		Clicks         int   `sql:"clicks"`
+		UniqueVisitors int   `sql:"unique_visitors"`
+	}
+	err = b.QueryClicks().
+		Where(inRange...).
+		Modify(func(s *sql.Selector) {
+			s.Select(
+				sql.As(clickBucket(s, step), "bucket"),
+				sql.As(sql.Count("*"), "clicks"),
+				sql.As(sql.Count(sql.Distinct(s.C(clickevent.FieldVisitorHash))), "unique_visitors"),
+			).GroupBy("bucket")
+		}).
+		Scan(c.Request.Context(), &buckets)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch click events"})
+		return
+	}
+
+	visitors, err := b.QueryClicks().
+		Where(inRange...).
+		Modify(func(s *sql.Selector) {
+			s.Select(sql.Count(sql.Distinct(s.C(clickevent.FieldVisitorHash))))
+		}).
+		Int(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch click events"})
+		return
+	}
+
+	top := []referrerCount{}
+	err = b.QueryClicks().
+		Where(append(inRange, clickevent.ReferrerHostNEQ(""))...).
+		Modify(func(s *sql.Selector) {
+			s.Select(
+				sql.As(s.C(clickevent.FieldReferrerHost), "host"),
+				sql.As(sql.Count("*"), "clicks"),
+			).
+				GroupBy(s.C(clickevent.FieldReferrerHost)).
+				OrderBy(sql.Desc("clicks"), s.C(clickevent.FieldReferrerHost)).
+				Limit(topReferrers)
+		}).
+		Scan(c.Request.Context(), &top)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch click events"})
+		return
+	}
+
+	// Pre-fill the series so buckets without clicks are reported as zero
+	series := make([]statsBucket, 0, int(to.Sub(from)/step)+1)
+	for start := from; start.Before(to); start = start.Add(step) {
+		series = append(series, statsBucket{Start: start})
+	}
+	first := from.Unix() / int64(step/time.Second)
+	totalClicks := 0
+	for _, row := range buckets {
+		i := int(row.Bucket - first)
+		if i < 0 || i >= len(series) {
+			continue
+		}
+		series[i].Clicks = row.Clicks
+		series[i].UniqueVisitors = row.UniqueVisitors
+		totalClicks += row.Clicks
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bookmark_id":     b.ID,
+		"visit_count":     b.VisitCount,
+		"from":            from,
+		"to":              to,
+		"bucket":          req.Bucket,
+		"total_clicks":    totalClicks,
+		"unique_visitors": visitors,
+		"series":          series,
+		"top_referrers":   top,
+	})
+}
+
+// clickBucket returns the SQL expression numbering the step sized bucket,
+// counted from the Unix epoch, that a click's occurred_at falls in. Buckets
+// of an hour or a day line up with time.Truncate in UTC.
+func clickBucket(s *sql.Selector, step time.Duration) string {
+	column := s.C(clickevent.FieldOccurredAt)
+	seconds := int64(step / time.Second)
+	if s.Dialect() == dialect.Postgres {
+		return fmt.Sprintf("CAST(FLOOR(EXTRACT(EPOCH FROM %s) / %d) AS BIGINT)", column, seconds)
+	}
+	return fmt.Sprintf("(CAST(strftime('%%s', %s) AS INTEGER) / %d)", column, seconds)
+}
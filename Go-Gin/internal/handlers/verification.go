@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/user"
+	"bookmark-shortener/internal/baseurl"
+	"bookmark-shortener/internal/mail"
+	"bookmark-shortener/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type VerificationHandler struct {
+	client  *ent.Client
+	outbox  *mail.Outbox
+	secret  []byte
+	ttl     time.Duration
+	baseURL *baseurl.Resolver
+}
+
+// NewVerificationHandler mails signed links valid for ttl that verify a
+// user's email address. No state is kept for unverified links.
+func NewVerificationHandler(client *ent.Client, outbox *mail.Outbox, secret string, ttl time.Duration, baseURL *baseurl.Resolver) *VerificationHandler {
+	return &VerificationHandler{
+		client:  client,
+		outbox:  outbox,
+		secret:  []byte(secret),
+		ttl:     ttl,
+		baseURL: baseURL,
+	}
+}
+
+// Verify marks the email of the link's user verified. Following a link
+// again is harmless.
+func (h *VerificationHandler) Verify(c *gin.Context) {
+	invalid := gin.H{"error": "Invalid or expired verification link"}
+
+	claims, err := utils.ValidateEmailToken(c.Query("token"), h.secret)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, invalid)
+		return
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, invalid)
+		return
+	}
+
+	// Only verify the address the link was sent to
+	n, err := h.client.User.Update().
+		Where(
+			user.ID(userID),
+			user.Email(claims.Email),
+			user.EmailVerifiedAtIsNil(),
+		).
+		SetEmailVerifiedAt(time.Now()).
+		Save(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if n == 0 {
+		verified, err := h.client.User.Query().
+			Where(user.ID(userID), user.Email(claims.Email), user.EmailVerifiedAtNotNil()).
+			Exist(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		if !verified {
+			c.JSON(http.StatusBadRequest, invalid)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified"})
+}
+
+// Resend mails the authenticated user a new verification link.
+func (h *VerificationHandler) Resend(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		return
+	}
+
+	u, err := h.client.User.Get(c.Request.Context(), userID)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	if u.EmailVerifiedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already verified"})
+		return
+	}
+
+	if err := h.send(c, u); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Verification email sent"})
+}
+
+// send mails u a verification link in the background.
+func (h *VerificationHandler) send(c *gin.Context, u *ent.User) error {
+	token, err := utils.GenerateEmailToken(u.ID.String(), u.Email, h.ttl, h.secret)
+	if err != nil {
+		return err
+	}
+	link := h.baseURL.Resolve(c.Request) + "/auth/verify?token=" + url.QueryEscape(token)
+
+	h.outbox.Send(c.Request.Context(), mail.Message{
+		To:      u.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Confirm that this is your email address by opening this link:\n\n    %s\n\n"+
+			"It expires in %d hours. If you didn't create an account, ignore this email.\n",
+			link, int(h.ttl.Hours())),
+	}, "user_id", u.ID)
+	return nil
+}
@@ -0,0 +1,132 @@
+// Package lockout slows down and then blocks repeated failed logins.
+package lockout
+
+import (
+	"sync"
+	"time"
+)
+
+// Policy allows FreeFailures failed attempts in a row, then makes clients
+// wait BaseDelay before the next attempt, doubling with every further
+// failure. After MaxFailures the key is locked for Duration. Failures older
+// than Duration are forgotten. A zero MaxFailures disables the policy.
+type Policy struct {
+	FreeFailures int
+	MaxFailures  int
+	BaseDelay    time.Duration
+	Duration     time.Duration
+}
+
+func (p Policy) Enabled() bool {
+	return p.MaxFailures > 0
+}
+
+// Until returns when the next attempt is allowed after failures, the last
+// of which happened at last. The zero time means right away.
+func (p Policy) Until(failures int, last time.Time) time.Time {
+	switch {
+	case !p.Enabled():
+		return time.Time{}
+	case failures >= p.MaxFailures:
+		return last.Add(p.Duration)
+	case failures <= p.FreeFailures:
+		return time.Time{}
+	}
+	delay := p.BaseDelay
+	for i := p.FreeFailures + 1; i < failures && delay < p.Duration; i++ {
+		delay *= 2
+	}
+	return last.Add(min(delay, p.Duration))
+}
+
+// Expired reports whether a failure at last is old enough to be forgotten.
+func (p Policy) Expired(last, now time.Time) bool {
+	return !now.Before(last.Add(p.Duration))
+}
+
+// sweepInterval bounds how often the tracker looks for expired entries.
+const sweepInterval = time.Minute
+
+type entry struct {
+	failures int
+	last     time.Time
+}
+
+// Tracker counts failures by key in memory, for clients that have no
+// database record such as IP addresses and unknown emails.
+type Tracker struct {
+	policy    Policy
+	mu        sync.Mutex
+	now       func() time.Time
+	entries   map[string]*entry
+	lastSweep time.Time
+}
+
+func NewTracker(policy Policy) *Tracker {
+	return &Tracker{
+		policy:  policy,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+// RetryAfter returns how long key has to wait before its next attempt.
+func (t *Tracker) RetryAfter(key string) time.Duration {
+	if !t.policy.Enabled() {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	e, ok := t.entries[key]
+	if !ok {
+		return 0
+	}
+	if t.policy.Expired(e.last, now) {
+		delete(t.entries, key)
+		return 0
+	}
+	return max(t.policy.Until(e.failures, e.last).Sub(now), 0)
+}
+
+// Fail records a failed attempt for key and returns the failures counted
+// so far.
+func (t *Tracker) Fail(key string) int {
+	if !t.policy.Enabled() {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.sweep(now)
+	e, ok := t.entries[key]
+	if !ok || t.policy.Expired(e.last, now) {
+		e = &entry{}
+		t.entries[key] = e
+	}
+	e.failures++
+	e.last = now
+	return e.failures
+}
+
+// Reset forgets the failures of key.
+func (t *Tracker) Reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.entries, key)
+}
+
+func (t *Tracker) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < sweepInterval {
+		return
+	}
+	t.lastSweep = now
+	for key, e := range t.entries {
+		if t.policy.Expired(e.last, now) {
+			delete(t.entries, key)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+)
+
+var policy = Policy{FreeFailures: 2, MaxFailures: 6, BaseDelay: time.Second, Duration: time.Minute}
+
+func TestPolicyUntil(t *testing.T) {
+	last := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{5, 4 * time.Second},
+		{6, time.Minute},
+		{20, time.Minute},
+	}
+	for _, tt := range tests {
+		until := policy.Until(tt.failures, last)
+		if (tt.want == 0 && !until.IsZero()) || (tt.want != 0 && until.Sub(last) != tt.want) {
+			t.Errorf("Until(%d) = %v after last, want %v", tt.failures, until.Sub(last), tt.want)
+		}
+	}
+
+	// Without free failures and backoff, the key locks at the maximum
+	lockOnly := Policy{FreeFailures: 3, MaxFailures: 3, Duration: time.Minute}
+	if got := lockOnly.Until(3, last); got.Sub(last) != time.Minute {
+		t.Errorf("lock-only Until(3) = %v after last", got.Sub(last))
+	}
+	if got := (Policy{}).Until(100, last); !got.IsZero() {
+		t.Errorf("disabled policy Until = %v", got)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := NewTracker(policy)
+	tracker.now = func() time.Time { return now }
+
+	for range 2 {
+		tracker.Fail("k")
+	}
+	if wait := tracker.RetryAfter("k"); wait != 0 {
+		t.Fatalf("after free failures: wait %v", wait)
+	}
+	tracker.Fail("k")
+	if wait := tracker.RetryAfter("k"); wait != time.Second {
+		t.Fatalf("after backoff starts: wait %v, want 1s", wait)
+	}
+	if wait := tracker.RetryAfter("other"); wait != 0 {
+		t.Fatalf("other key: wait %v", wait)
+	}
+
+	for range 3 {
+		tracker.Fail("k")
+	}
+	now = now.Add(30 * time.Second)
+	if wait := tracker.RetryAfter("k"); wait != 30*time.Second {
+		t.Fatalf("locked: wait %v, want 30s", wait)
+	}
+
+	// Failures are forgotten once the lockout has passed
+	now = now.Add(30 * time.Second)
+	if wait := tracker.RetryAfter("k"); wait != 0 {
+		t.Fatalf("after lockout: wait %v", wait)
+	}
+	if n := tracker.Fail("k"); n != 1 {
+		t.Fatalf("failures after lockout = %d, want 1", n)
+	}
+
+	tracker.Reset("k")
+	if _, ok := tracker.entries["k"]; ok {
+		t.Fatal("Reset kept the entry")
+	}
+}
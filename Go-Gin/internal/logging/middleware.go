@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"bookmark-shortener/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds IDs accepted from clients.
+const maxRequestIDLength = 128
+
+// Middleware assigns every request an ID, taken from a well-formed
+// X-Request-ID header or generated, and echoes it in the response. It
+// stores a logger with the request and trace IDs in the request context
+// and writes an access log line once the request completes. Run it after
+// the tracing middleware so the trace ID is known.
+func Middleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+		c.Set("request_id", requestID)
+
+		reqLogger := logger.With("request_id", requestID)
+		if traceID := tracing.TraceID(c.Request.Context()); traceID != "" {
+			reqLogger = reqLogger.With("trace_id", traceID)
+		}
+		c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), reqLogger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
+		}
+		if errs := c.Errors.ByType(gin.ErrorTypePrivate).String(); errs != "" {
+			attrs = append(attrs, slog.String("errors", errs))
+		}
+		reqLogger.LogAttrs(c.Request.Context(), level, "Request handled", attrs...)
+	}
+}
+
+// Recovery turns a panic into a 500 response and logs it with the request's
+// context.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, err any) {
+		FromContext(c.Request.Context()).Error("Panic while handling request", "error", err, "stack", string(debug.Stack()))
+		c.AbortWithStatus(500)
+	})
+}
+
+// validRequestID accepts printable ASCII without spaces, so client IDs
+// cannot break log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
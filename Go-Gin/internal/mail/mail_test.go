@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m, err := NewFileMailer(dir, "Shortener <noreply@sho.rt>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := Message{To: "alice@example.com", Subject: "Hello", Body: "Line one\nLine two\n"}
+	if err := m.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil || len(files) != 1 || !strings.HasSuffix(files[0].Name(), ".eml") {
+		t.Fatalf("files %v, err %v", files, err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"From: Shortener <noreply@sho.rt>\r\n",
+		"To: alice@example.com\r\n",
+		"Subject: Hello\r\n",
+		"\r\n\r\nLine one\r\nLine two\r\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("message is missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestHeaderInjection(t *testing.T) {
+	m, err := NewFileMailer(t.TempDir(), "noreply@sho.rt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []Message{
+		{To: "alice@example.com\r\nBcc: eve@example.com", Subject: "Hello"},
+		{To: "alice@example.com", Subject: "Hello\nBcc: eve@example.com"},
+	} {
+		if err := m.Send(context.Background(), msg); err == nil {
+			t.Errorf("Send(%q, %q) succeeded", msg.To, msg.Subject)
+		}
+	}
+}
+
+type recordingMailer struct {
+	sent chan Message
+}
+
+func (m recordingMailer) Send(ctx context.Context, msg Message) error {
+	m.sent <- msg
+	return nil
+}
+
+func TestOutboxWait(t *testing.T) {
+	mailer := recordingMailer{sent: make(chan Message, 1)}
+	outbox := NewOutbox(mailer)
+
+	outbox.Send(context.Background(), Message{To: "alice@example.com", Subject: "Hello"})
+	if err := outbox.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-mailer.sent:
+		if msg.To != "alice@example.com" {
+			t.Fatalf("sent %+v", msg)
+		}
+	default:
+		t.Fatal("Wait returned before the message was sent")
+	}
+}
@@ -0,0 +1,53 @@
+package mail
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"bookmark-shortener/internal/logging"
+)
+
+// sendTimeout bounds each background delivery.
+const sendTimeout = 30 * time.Second
+
+// Outbox sends messages in the background, so requests neither wait for
+// delivery nor reveal through their timing whether a message was sent.
+type Outbox struct {
+	mailer  Mailer
+	pending sync.WaitGroup
+}
+
+func NewOutbox(mailer Mailer) *Outbox {
+	return &Outbox{mailer: mailer}
+}
+
+// Send delivers msg in the background. Failures are logged with the logger
+// of ctx and attrs; ctx being canceled does not stop delivery.
+func (o *Outbox) Send(ctx context.Context, msg Message, attrs ...any) {
+	log := logging.FromContext(ctx).With(attrs...)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+	o.pending.Add(1)
+	go func() {
+		defer o.pending.Done()
+		defer cancel()
+		if err := o.mailer.Send(ctx, msg); err != nil {
+			log.Error("Failed to send email", "error", err, "subject", msg.Subject)
+		}
+	}()
+}
+
+// Wait blocks until messages being sent are done or ctx ends.
+func (o *Outbox) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		o.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
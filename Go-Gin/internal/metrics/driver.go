@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"entgo.io/ent/dialect"
+)
+
+// Driver wraps an Ent driver to record how long every statement takes.
+func Driver(drv dialect.Driver) dialect.Driver {
+	return &timedDriver{drv}
+}
+
+type timedDriver struct {
+	dialect.Driver
+}
+
+func (d *timedDriver) Exec(ctx context.Context, query string, args, v any) error {
+	defer observe("exec", time.Now())
+	return d.Driver.Exec(ctx, query, args, v)
+}
+
+func (d *timedDriver) Query(ctx context.Context, query string, args, v any) error {
+	defer observe("query", time.Now())
+	return d.Driver.Query(ctx, query, args, v)
+}
+
+func (d *timedDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	return d.BeginTx(ctx, nil)
+}
+
+func (d *timedDriver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	defer observe("begin", time.Now())
+
+	var tx dialect.Tx
+	var err error
+	if b, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	}); ok {
+		tx, err = b.BeginTx(ctx, opts)
+	} else {
+		tx, err = d.Driver.Tx(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &timedTx{tx}, nil
+}
+
+// ExecContext and QueryContext back the raw SQL methods of the Ent client.
+func (d *timedDriver) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	drv, ok := d.Driver.(interface {
+		ExecContext(context.Context, string, ...any) (sql.Result, error)
+	})
+	if !ok {
+		return nil, errors.New("metrics: driver does not support ExecContext")
+	}
+	defer observe("exec", time.Now())
+	return drv.ExecContext(ctx, query, args...)
+}
+
+func (d *timedDriver) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	drv, ok := d.Driver.(interface {
+		QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+	})
+	if !ok {
+		return nil, errors.New("metrics: driver does not support QueryContext")
+	}
+	defer observe("query", time.Now())
+	return drv.QueryContext(ctx, query, args...)
+}
+
+type timedTx struct {
+	dialect.Tx
+}
+
+func (t *timedTx) Exec(ctx context.Context, query string, args, v any) error {
+	defer observe("exec", time.Now())
+	return t.Tx.Exec(ctx, query, args, v)
+}
+
+func (t *timedTx) Query(ctx context.Context, query string, args, v any) error {
+	defer observe("query", time.Now())
+	return t.Tx.Query(ctx, query, args, v)
+}
+
+func (t *timedTx) Commit() error {
+	defer observe("commit", time.Now())
+	return t.Tx.Commit()
+}
+
+func (t *timedTx) Rollback() error {
+	defer observe("rollback", time.Now())
+	return t.Tx.Rollback()
+}
+
+func observe(operation string, start time.Time) {
+	dbDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,106 @@
+// Package metrics exposes the server's Prometheus metrics.
+package metrics
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"bookmark-shortener/internal/cache"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric of the server, plus Go runtime and process
+// stats.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	httpRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method and route template.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	dbDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Database statement latency by operation.",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14),
+	}, []string{"operation"})
+
+	redirects = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "redirects_total",
+		Help: "Short URL lookups by result.",
+	}, []string{"result"})
+)
+
+// Redirect results.
+const (
+	RedirectFound    = "found"
+	RedirectNotFound = "not_found"
+	RedirectExpired  = "expired"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		cacheCollector{},
+	)
+	// Report every result from the start so rates work before the first 404
+	for _, result := range []string{RedirectFound, RedirectNotFound, RedirectExpired} {
+		redirects.WithLabelValues(result)
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRedirect counts the result of a short code lookup.
+func ObserveRedirect(result string) {
+	redirects.WithLabelValues(result).Inc()
+}
+
+var shortCodeCache atomic.Pointer[cache.ShortCodeCache]
+
+// WatchShortCodeCache reports the hit and miss counts of c, replacing any
+// cache watched before.
+func WatchShortCodeCache(c *cache.ShortCodeCache) {
+	shortCodeCache.Store(c)
+}
+
+var cacheLookups = prometheus.NewDesc(
+	"shortcode_cache_lookups_total",
+	"Short code cache lookups by result: hit, negative_hit (cached 404) or miss.",
+	[]string{"result"}, nil,
+)
+
+// cacheCollector reads the counters the cache keeps anyway instead of
+// duplicating them.
+type cacheCollector struct{}
+
+func (cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheLookups
+}
+
+func (cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	c := shortCodeCache.Load()
+	if c == nil {
+		return
+	}
+	stats := c.Stats()
+	ch <- prometheus.MustNewConstMetric(cacheLookups, prometheus.CounterValue, float64(stats.Hits), "hit")
+	ch <- prometheus.MustNewConstMetric(cacheLookups, prometheus.CounterValue, float64(stats.NegativeHits), "negative_hit")
+	ch <- prometheus.MustNewConstMetric(cacheLookups, prometheus.CounterValue, float64(stats.Misses), "miss")
+}
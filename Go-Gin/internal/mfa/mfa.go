@@ -0,0 +1,92 @@
+// Package mfa implements TOTP second factors (RFC 6238) and one-time
+// recovery codes.
+package mfa
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// Period is how long each code is valid.
+	Period = 30 * time.Second
+	// Skew is how many periods before and after the current one are still
+	// accepted, for clocks that are slightly off.
+	Skew = 1
+
+	RecoveryCodeCount = 10
+)
+
+// Enrollment is a new TOTP secret for an authenticator app.
+type Enrollment struct {
+	Secret string
+	// URI is the otpauth:// URI apps read, usually from a QR code.
+	URI string
+}
+
+// Enroll generates a secret for account, shown in apps under issuer.
+func Enroll(issuer, account string) (Enrollment, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: account,
+		Period:      uint(Period.Seconds()),
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return Enrollment{}, err
+	}
+	return Enrollment{Secret: key.Secret(), URI: key.URL()}, nil
+}
+
+// Validate checks code against secret at now. Codes from time steps up to
+// lastStep were used already and are rejected, so an observed code can't
+// be replayed. It returns the step of the accepted code, to be stored as
+// the next lastStep.
+func Validate(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	current := now.Unix() / int64(Period.Seconds())
+	for step := current - Skew; step <= current+Skew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		ok, err := hotp.ValidateCustom(code, uint64(step), secret, hotp.ValidateOpts{
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err == nil && ok {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// recoveryEncoding spells recovery codes in lower case without padding.
+var recoveryEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// GenerateRecoveryCodes returns RecoveryCodeCount random codes such as
+// "k3hx7-q2mzd". Only their hashes should be stored.
+func GenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		code := recoveryEncoding.EncodeToString(b)[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode undoes the formatting users may add or drop when
+// typing a recovery code, before it is hashed.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,69 @@
+package mfa
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+)
+
+func TestValidate(t *testing.T) {
+	enrollment, err := Enroll("Bookmark Shortener", "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(enrollment.URI)
+	if err != nil || u.Scheme != "otpauth" || u.Query().Get("secret") != enrollment.Secret || u.Query().Get("issuer") != "Bookmark Shortener" {
+		t.Fatalf("URI %q", enrollment.URI)
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 15, 0, time.UTC)
+	code, err := totp.GenerateCode(enrollment.Secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step, ok := Validate(enrollment.Secret, code, now, 0)
+	if !ok || step != now.Unix()/30 {
+		t.Fatalf("Validate = %d, %v", step, ok)
+	}
+	// Codes are accepted one period early or late
+	if _, ok := Validate(enrollment.Secret, code, now.Add(Period), 0); !ok {
+		t.Fatal("code from the previous period was rejected")
+	}
+	if _, ok := Validate(enrollment.Secret, code, now.Add(2*Period), 0); ok {
+		t.Fatal("code from two periods ago was accepted")
+	}
+	// A used code can't be replayed
+	if _, ok := Validate(enrollment.Secret, code, now, step); ok {
+		t.Fatal("replayed code was accepted")
+	}
+	if _, ok := Validate(enrollment.Secret, "000000x", now, 0); ok {
+		t.Fatal("malformed code was accepted")
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != RecoveryCodeCount {
+		t.Fatalf("%d codes", len(codes))
+	}
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' || seen[code] {
+			t.Fatalf("bad or repeated code %q", code)
+		}
+		seen[code] = true
+	}
+
+	if got := NormalizeRecoveryCode(" K3HX7 Q2MZD "); got != "k3hx7q2mzd" {
+		t.Errorf("NormalizeRecoveryCode = %q", got)
+	}
+	if NormalizeRecoveryCode(codes[0]) != NormalizeRecoveryCode(codes[0][:5]+codes[0][6:]) {
+		t.Error("dash changes the normalized code")
+	}
+}
@@ -1,21 +1,33 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
+	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/refreshtoken"
+	"bookmark-shortener/ent/user"
+	"bookmark-shortener/internal/session"
 	"bookmark-shortener/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AuthMiddleware struct {
-	secret []byte
+	client   *ent.Client
+	secret   []byte
+	sessions session.Store
 }
 
-func NewAuthMiddleware(secret string) *AuthMiddleware {
+// NewAuthMiddleware validates bearer JWTs, or opaque session IDs when a
+// session store is given.
+func NewAuthMiddleware(client *ent.Client, secret string, sessions session.Store) *AuthMiddleware {
 	return &AuthMiddleware{
-		secret: []byte(secret),
+		client:   client,
+		secret:   []byte(secret),
+		sessions: sessions,
 	}
 }
 
@@ -35,14 +47,91 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		claims, err := utils.ValidateToken(tokenString, m.secret)
+		if m.sessions != nil {
+			m.authenticateSession(c, tokenString)
+		} else {
+			m.authenticateJWT(c, tokenString)
+		}
+		if c.IsAborted() {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireVerifiedEmail rejects users who haven't verified their email
+// address with 403. Run it after RequireAuth.
+func (m *AuthMiddleware) RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("user_id"))
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+			c.Abort()
+			return
+		}
+
+		verified, err := m.client.User.Query().
+			Where(user.ID(userID), user.EmailVerifiedAtNotNil()).
+			Exist(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			c.Abort()
+			return
+		}
+		if !verified {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Verify your email address first"})
 			c.Abort()
 			return
 		}
 
-		c.Set("user_id", claims.UserID)
 		c.Next()
 	}
 }
+
+func (m *AuthMiddleware) authenticateJWT(c *gin.Context, tokenString string) {
+	claims, err := utils.ValidateToken(tokenString, m.secret)
+	if err != nil || claims.ID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		c.Abort()
+		return
+	}
+
+	// Reject access tokens whose refresh token family has been revoked
+	revoked, err := m.client.RefreshToken.Query().
+		Where(
+			refreshtoken.AccessJti(claims.ID),
+			refreshtoken.RevokedAtNotNil(),
+		).
+		Exist(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		c.Abort()
+		return
+	}
+	if revoked {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+		c.Abort()
+		return
+	}
+
+	c.Set("user_id", claims.UserID)
+	c.Set("token_id", claims.ID)
+	c.Set("token_family", claims.FamilyID)
+}
+
+func (m *AuthMiddleware) authenticateSession(c *gin.Context, sessionID string) {
+	userID, err := m.sessions.Validate(c.Request.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid session"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Session store error"})
+		}
+		c.Abort()
+		return
+	}
+
+	c.Set("user_id", userID)
+	c.Set("session_id", sessionID)
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
@@ -11,6 +16,74 @@ type RegisterRequest struct {
 }
 
 type BookmarkRequest struct {
-	Title string `json:"title" binding:"required"`
-	URL   string `json:"url" binding:"required,url"`
+	Title     string     `json:"title" binding:"required"`
+	URL       string     `json:"url" binding:"required,url"`
+	Alias     string     `json:"alias"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	MaxVisits *int       `json:"max_visits" binding:"omitempty,min=1"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// MFAVerifyRequest finishes a login with a TOTP code or, when the
+// authenticator is lost, a recovery code.
+type MFAVerifyRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code" binding:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// UpdateBookmarkRequest is a partial update; nil fields are left unchanged.
+// The limits can also be removed by sending them as null.
+type UpdateBookmarkRequest struct {
+	Title     *string             `json:"title" binding:"omitempty,min=1"`
+	URL       *string             `json:"url" binding:"omitempty,url"`
+	ExpiresAt Nullable[time.Time] `json:"expires_at"`
+	MaxVisits Nullable[int]       `json:"max_visits"`
+}
+
+// Nullable is a JSON field that tells an absent value, which leaves the
+// field unchanged, apart from an explicit null, which clears it.
+type Nullable[T any] struct {
+	// Set is true when the field was sent, even as null.
+	Set bool
+	// Value is nil when the field was null.
+	Value *T
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	return json.Unmarshal(data, &n.Value)
+}
+
+type ListBookmarksQuery struct {
+	Limit         int       `form:"limit" binding:"omitempty,min=1,max=200"`
+	Cursor        string    `form:"cursor"`
+	Sort          string    `form:"sort" binding:"omitempty,oneof=created_at visit_count title"`
+	Order         string    `form:"order" binding:"omitempty,oneof=asc desc"`
+	Query         string    `form:"q"`
+	Domain        string    `form:"domain"`
+	CreatedAfter  time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type StatsQuery struct {
+	Bucket string    `form:"bucket" binding:"omitempty,oneof=hour day"`
+	From   time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
 }
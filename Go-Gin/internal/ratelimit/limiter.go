@@ -0,0 +1,122 @@
+// Package ratelimit throttles clients with token buckets kept in a
+// pluggable store.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Period. Tokens are refilled continuously, so a
+// client can burst up to Requests and then continue at the average rate.
+// The zero Limit disables limiting.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit parses "N/period", such as "10/1m" or "5/s", or "off".
+func ParseLimit(s string) (Limit, error) {
+	if s == "off" || s == "0" {
+		return Limit{}, nil
+	}
+	n, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected requests/period such as 10/1m, or off", s)
+	}
+	requests, err := strconv.Atoi(n)
+	if err != nil || requests < 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected requests/period such as 10/1m, or off", s)
+	}
+	// Accept a bare unit, as in 5/s
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected requests/period such as 10/1m, or off", s)
+	}
+	if requests == 0 {
+		return Limit{}, nil
+	}
+	return Limit{Requests: requests, Period: d}, nil
+}
+
+// UnmarshalText lets limits be read from configuration.
+func (l *Limit) UnmarshalText(text []byte) error {
+	parsed, err := ParseLimit(string(text))
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+// MarshalText renders limits in the format ParseLimit reads.
+func (l Limit) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// Enabled reports whether the limit restricts anything.
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// Result describes a client's bucket after a request was counted.
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until the next request is allowed, zero when
+	// this one was.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store keeps token buckets by key. Stores shared between instances make
+// limits apply across the whole deployment.
+type Store interface {
+	// Take counts a request against the bucket for key.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// refill returns the tokens in a bucket after elapsed time.
+func refill(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	if elapsed <= 0 {
+		return tokens
+	}
+	return math.Min(float64(limit.Requests), tokens+elapsed.Seconds()*rate(limit))
+}
+
+// result describes a bucket holding tokens after a request was counted.
+func result(tokens float64, allowed bool, limit Limit) Result {
+	r := Result{
+		Allowed:   allowed,
+		Remaining: int(tokens),
+		Reset:     seconds((float64(limit.Requests) - tokens) / rate(limit)),
+	}
+	if !allowed {
+		r.RetryAfter = seconds((1 - tokens) / rate(limit))
+	}
+	return r
+}
+
+// rate is the refill rate in tokens per second.
+func rate(limit Limit) float64 {
+	return float64(limit.Requests) / limit.Period.Seconds()
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
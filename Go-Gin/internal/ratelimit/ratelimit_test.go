@@ -0,0 +1,210 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeClock is advanced by hand.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newMemoryStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.Now
+	return store, clock
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Limit
+	}{
+		{"10/1m", Limit{10, time.Minute}},
+		{"5/s", Limit{5, time.Second}},
+		{"100/30s", Limit{100, 30 * time.Second}},
+		{"off", Limit{}},
+		{"0/1m", Limit{}},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, invalid := range []string{"", "10", "ten/1m", "10/", "10/soon", "-1/1m", "10/-1m"} {
+		if _, err := ParseLimit(invalid); err == nil {
+			t.Errorf("ParseLimit(%q) succeeded", invalid)
+		}
+	}
+}
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	store, clock := newMemoryStore()
+	ctx := context.Background()
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+
+	// The full burst is available at once
+	for i := 2; i >= 0; i-- {
+		res, _ := store.Take(ctx, "k", limit)
+		if !res.Allowed || res.Remaining != i {
+			t.Fatalf("burst request: %+v, want allowed with %d remaining", res, i)
+		}
+	}
+	res, _ := store.Take(ctx, "k", limit)
+	if res.Allowed || res.RetryAfter != time.Second || res.Reset != 3*time.Second {
+		t.Fatalf("over limit: %+v", res)
+	}
+
+	// Other keys have their own bucket
+	if res, _ := store.Take(ctx, "other", limit); !res.Allowed {
+		t.Fatal("other key was limited")
+	}
+
+	// Tokens come back at the average rate
+	clock.Advance(time.Second)
+	if res, _ := store.Take(ctx, "k", limit); !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("after refill: %+v", res)
+	}
+	if res, _ := store.Take(ctx, "k", limit); res.Allowed {
+		t.Fatal("second request after one refill was allowed")
+	}
+
+	// Idle buckets fill up, but not beyond the burst
+	clock.Advance(time.Hour)
+	res, _ = store.Take(ctx, "k", limit)
+	if !res.Allowed || res.Remaining != 2 {
+		t.Fatalf("after idling: %+v", res)
+	}
+}
+
+func TestMemoryStoreSweepsIdleBuckets(t *testing.T) {
+	store, clock := newMemoryStore()
+	ctx := context.Background()
+	limit := Limit{Requests: 1, Period: time.Second}
+
+	store.Take(ctx, "idle", limit)
+	clock.Advance(sweepInterval)
+	store.Take(ctx, "active", limit)
+
+	if _, ok := store.buckets["idle"]; ok {
+		t.Fatal("idle bucket was not swept")
+	}
+	if _, ok := store.buckets["active"]; !ok {
+		t.Fatal("active bucket was swept")
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("store unavailable")
+}
+
+func newEngine(store Store, limit Limit) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", Middleware(store, Policy{Name: "test", Limit: limit, Key: ByIP}), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return r
+}
+
+func get(r *gin.Engine, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddlewareHeaders(t *testing.T) {
+	store, clock := newMemoryStore()
+	r := newEngine(store, Limit{Requests: 2, Period: time.Minute})
+
+	w := get(r, "192.0.2.1:1234")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status %d", w.Code)
+	}
+	want := map[string]string{
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "1",
+		"RateLimit-Reset":     "30",
+		"RateLimit-Policy":    "2;w=60",
+	}
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+
+	get(r, "192.0.2.1:1234")
+	w = get(r, "192.0.2.1:1234")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status %d, want 429", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+	if got := w.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining = %q, want 0", got)
+	}
+
+	// Another client is not affected
+	if w := get(r, "192.0.2.2:1234"); w.Code != http.StatusNoContent {
+		t.Fatalf("other client: status %d", w.Code)
+	}
+
+	clock.Advance(30 * time.Second)
+	if w := get(r, "192.0.2.1:1234"); w.Code != http.StatusNoContent {
+		t.Fatalf("after Retry-After: status %d", w.Code)
+	}
+}
+
+func TestMiddlewareDisabledAndFailingOpen(t *testing.T) {
+	for name, r := range map[string]*gin.Engine{
+		"disabled":      newEngine(failingStore{}, Limit{}),
+		"store failure": newEngine(failingStore{}, Limit{Requests: 1, Period: time.Minute}),
+	} {
+		w := get(r, "192.0.2.1:1234")
+		if w.Code != http.StatusNoContent {
+			t.Errorf("%s: status %d", name, w.Code)
+		}
+		if got := w.Header().Get("RateLimit-Limit"); got != "" {
+			t.Errorf("%s: RateLimit-Limit = %q", name, got)
+		}
+	}
+}
+
+func TestKeyFuncs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.RemoteAddr = "192.0.2.1:1234"
+
+	if got := ByUser(c); got != "ip:192.0.2.1" {
+		t.Errorf("anonymous ByUser = %q", got)
+	}
+	c.Set("user_id", "42")
+	if got := ByUser(c); got != "user:42" {
+		t.Errorf("ByUser = %q", got)
+	}
+	if got := ByAPIKey(c); got != "user:42" {
+		t.Errorf("ByAPIKey without a key = %q", got)
+	}
+	c.Request.Header.Set(APIKeyHeader, "secret-key")
+	if got := ByAPIKey(c); got == "" || got == "key:secret-key" {
+		t.Errorf("ByAPIKey = %q, want a hashed key", got)
+	}
+}
@@ -0,0 +1,73 @@
+// Package secretbox encrypts small secrets for storage with AES-256-GCM.
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the key length in bytes.
+const KeySize = 32
+
+var ErrDecrypt = errors.New("secretbox: message could not be decrypted")
+
+// Box seals and opens secrets with one key. Each secret is bound to a
+// context, such as the ID of the row it is stored in, so ciphertexts
+// copied elsewhere don't open.
+type Box struct {
+	aead cipher.AEAD
+}
+
+func New(key []byte) (*Box, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("secretbox: key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Box{aead: aead}, nil
+}
+
+// ParseKey decodes a hex key, as generated with `openssl rand -hex 32`.
+func ParseKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(s)
+	if err != nil || len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d hex-encoded bytes, generate one with `openssl rand -hex %d`", KeySize, KeySize)
+	}
+	return key, nil
+}
+
+// Seal encrypts plaintext for context and returns it base64 encoded with
+// its nonce.
+func (b *Box) Seal(plaintext []byte, context string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize(), b.aead.NonceSize()+len(plaintext)+b.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := b.aead.Seal(nonce, nonce, plaintext, []byte(context))
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value from Seal with the same context.
+func (b *Box) Open(sealed string, context string) ([]byte, error) {
+	data, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < b.aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	nonce, ciphertext := data[:b.aead.NonceSize()], data[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, []byte(context))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,63 @@
+package secretbox
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newBox(t *testing.T, fill byte) *Box {
+	t.Helper()
+	b, err := New(bytes.Repeat([]byte{fill}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSealOpen(t *testing.T) {
+	b := newBox(t, 1)
+
+	sealed, err := b.Seal([]byte("JBSWY3DPEHPK3PXP"), "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sealed, "JBSWY3DPEHPK3PXP") {
+		t.Fatal("sealed value contains the plaintext")
+	}
+	again, _ := b.Seal([]byte("JBSWY3DPEHPK3PXP"), "user-1")
+	if again == sealed {
+		t.Fatal("sealing twice gave the same ciphertext")
+	}
+
+	plaintext, err := b.Open(sealed, "user-1")
+	if err != nil || string(plaintext) != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("Open = %q, %v", plaintext, err)
+	}
+
+	tampered := []byte(sealed)
+	tampered[len(tampered)/2] ^= 1
+
+	for name, open := range map[string]func() ([]byte, error){
+		"other context": func() ([]byte, error) { return b.Open(sealed, "user-2") },
+		"other key":     func() ([]byte, error) { return newBox(t, 2).Open(sealed, "user-1") },
+		"tampered":      func() ([]byte, error) { return b.Open(string(tampered), "user-1") },
+		"truncated":     func() ([]byte, error) { return b.Open(sealed[:8], "user-1") },
+	} {
+		if _, err := open(); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("%s: err = %v, want ErrDecrypt", name, err)
+		}
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	if _, err := ParseKey(strings.Repeat("ab", KeySize)); err != nil {
+		t.Fatal(err)
+	}
+	for _, invalid := range []string{"", "secret", strings.Repeat("ab", KeySize-1), strings.Repeat("zz", KeySize)} {
+		if _, err := ParseKey(invalid); err == nil {
+			t.Errorf("ParseKey(%q) succeeded", invalid)
+		}
+	}
+}
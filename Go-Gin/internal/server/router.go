@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+
+	"bookmark-shortener/ent"
+	"bookmark-shortener/internal/analytics"
+	"bookmark-shortener/internal/background"
+	"bookmark-shortener/internal/baseurl"
+	"bookmark-shortener/internal/cache"
+	"bookmark-shortener/internal/config"
+	"bookmark-shortener/internal/handlers"
+	"bookmark-shortener/internal/logging"
+	"bookmark-shortener/internal/mail"
+	"bookmark-shortener/internal/metrics"
+	"bookmark-shortener/internal/middleware"
+	"bookmark-shortener/internal/ratelimit"
+	"bookmark-shortener/internal/tracing"
+	"bookmark-shortener/internal/visits"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Router is the application's HTTP handler together with the background
+// components its handlers depend on.
+type Router struct {
+	*gin.Engine
+
+	health  *handlers.HealthHandler
+	tasks   *background.Group
+	outbox  *mail.Outbox
+	counter *visits.Counter
+}
+
+// NewRouter wires handlers, middleware and routes for cfg on top of client.
+// It registers the cache invalidation hook on client, so every client should
+// back a single router. Call Close once the router no longer serves
+// requests.
+func NewRouter(cfg *config.Config, client *ent.Client) (*Router, error) {
+	// Initialize session store (nil unless AUTH_MODE=session)
+	sessions, err := cfg.InitSessionStore()
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize click analytics
+	geo, err := cfg.InitGeoIP()
+	if err != nil {
+		return nil, err
+	}
+	salt := cfg.ClickSalt
+	if salt == "" {
+		slog.Warn("CLICK_SALT is not set, unique visitor counts will reset on restart")
+		salt = uuid.NewString()
+	}
+	recorder := analytics.NewRecorder(client, geo, salt)
+
+	// Visit counts and click events are buffered and written in batches
+	counter := visits.NewCounter(visits.NewEntSink(client, recorder), cfg.VisitFlushInterval, cfg.VisitBatchSize)
+
+	// Short code lookups are cached; the hook invalidates entries on writes
+	shortCodes := cache.NewShortCodeCache(client, cfg.CacheSize, cfg.CacheTTL, cfg.CacheNegativeTTL)
+	client.Bookmark.Use(shortCodes.Hook())
+	metrics.WatchShortCodeCache(shortCodes)
+
+	// Links use BASE_URL unless a trusted proxy reports the client's URL
+	baseURL, err := baseurl.New(cfg.BaseURL, cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rate limit buckets, shared between instances with the redis store
+	limiter, err := cfg.InitRateLimitStore()
+	if err != nil {
+		return nil, err
+	}
+
+	// Verification and password reset emails are sent in the background
+	mailer, err := cfg.InitMailer()
+	if err != nil {
+		return nil, err
+	}
+	outbox := mail.NewOutbox(mailer)
+	// Work requests start but don't wait for, such as recording failed
+	// logins or looking up accounts for password resets
+	tasks := background.NewGroup()
+
+	// TOTP secrets are encrypted at rest; without a key 2FA is unavailable
+	totpBox, err := cfg.InitTOTPBox()
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize handlers
+	verificationHandler := handlers.NewVerificationHandler(client, outbox, cfg.JWTSecret, cfg.EmailVerificationTTL, baseURL)
+	mfaHandler := handlers.NewMFAHandler(client, cfg.JWTSecret, totpBox, cfg.TOTPIssuer, cfg.MFAChallengeTTL)
+	authHandler := handlers.NewAuthHandler(client, cfg.JWTSecret, sessions, verificationHandler, mfaHandler, tasks, cfg.AccountLockout(), cfg.IPLockout())
+	passwordResetHandler := handlers.NewPasswordResetHandler(client, outbox, tasks, sessions, cfg.PasswordResetTTL, cfg.PasswordResetURL)
+	bookmarkHandler := handlers.NewBookmarkHandler(client, baseURL)
+	redirectHandler := handlers.NewRedirectHandler(client, shortCodes, recorder, counter)
+	healthHandler := handlers.NewHealthHandler(client)
+
+	// Initialize middleware
+	authMiddleware := middleware.NewAuthMiddleware(client, cfg.JWTSecret, sessions)
+	authLimit := ratelimit.Middleware(limiter, ratelimit.Policy{Name: "auth", Limit: cfg.RateLimitAuth, Key: ratelimit.ByIP})
+	apiLimit := ratelimit.Middleware(limiter, ratelimit.Policy{Name: "api", Limit: cfg.RateLimitAPI, Key: ratelimit.ByUser})
+	redirectLimit := ratelimit.Middleware(limiter, ratelimit.Policy{Name: "redirect", Limit: cfg.RateLimitRedirect, Key: ratelimit.ByIP})
+	// Unverified accounts may be throwaways minting links for spam
+	verifiedEmail := func(c *gin.Context) { c.Next() }
+	if cfg.RequireVerifiedEmail {
+		verifiedEmail = authMiddleware.RequireVerifiedEmail()
+	}
+
+	// Setup routes
+	r := gin.New()
+	// Client IPs come from X-Forwarded-For only when a trusted proxy sent it
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, err
+	}
+	r.Use(tracing.Middleware(), logging.Middleware(slog.Default()), logging.Recovery(), metrics.Middleware())
+
+	// Metrics, unless they are served on the admin port
+	if cfg.MetricsPort == "" {
+		r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	}
+
+	// Health checks
+	r.GET("/healthz", healthHandler.Live)
+	r.GET("/readyz", healthHandler.Ready)
+
+	// Auth routes
+	auth := r.Group("/auth")
+	{
+		auth.POST("/register", authLimit, authHandler.Register)
+		auth.POST("/token", authLimit, authHandler.Login)
+		auth.POST("/2fa/verify", authLimit, authHandler.VerifyMFA)
+		auth.POST("/refresh", authLimit, authHandler.Refresh)
+		auth.POST("/password/forgot", authLimit, passwordResetHandler.Forgot)
+		auth.POST("/password/reset", authLimit, passwordResetHandler.Reset)
+		auth.POST("/2fa/setup", authMiddleware.RequireAuth(), authLimit, mfaHandler.Setup)
+		auth.POST("/2fa/confirm", authMiddleware.RequireAuth(), authLimit, mfaHandler.Confirm)
+		auth.GET("/verify", authLimit, verificationHandler.Verify)
+		auth.POST("/verify/resend", authMiddleware.RequireAuth(), authLimit, verificationHandler.Resend)
+		auth.POST("/logout", authMiddleware.RequireAuth(), authHandler.Logout)
+	}
+
+	// Protected bookmark routes
+	bookmarks := r.Group("/bookmarks")
+	bookmarks.Use(authMiddleware.RequireAuth(), apiLimit)
+	{
+		bookmarks.POST("/create", verifiedEmail, bookmarkHandler.Create)
+		bookmarks.GET("/get", bookmarkHandler.GetAll)
+		bookmarks.GET("/get/:id", bookmarkHandler.GetByID)
+		bookmarks.GET("/get/:id/stats", bookmarkHandler.Stats)
+		bookmarks.DELETE("/delete/:id", bookmarkHandler.Delete)
+		bookmarks.PATCH("/:id", bookmarkHandler.Update)
+	}
+
+	// Short URL redirect
+	r.GET("/:code", redirectLimit, redirectHandler.Redirect)
+
+	return &Router{
+		Engine:  r,
+		health:  healthHandler,
+		tasks:   tasks,
+		outbox:  outbox,
+		counter: counter,
+	}, nil
+}
+
+// Drain makes /readyz fail so load balancers stop sending traffic.
+func (r *Router) Drain() {
+	r.health.Drain()
+}
+
+// Flush waits for work started by earlier requests and writes buffered
+// visit counts and click events now.
+func (r *Router) Flush(ctx context.Context) error {
+	if err := r.tasks.Wait(ctx); err != nil {
+		return err
+	}
+	return r.counter.Flush(ctx)
+}
+
+// Close stops background work, waits for emails being sent and flushes what
+// is buffered.
+func (r *Router) Close(ctx context.Context) error {
+	if err := r.tasks.Wait(ctx); err != nil {
+		return err
+	}
+	if err := r.outbox.Wait(ctx); err != nil {
+		return err
+	}
+	return r.counter.Close(ctx)
+}
@@ -0,0 +1,1482 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/enttest"
+	"bookmark-shortener/ent/passwordresettoken"
+	"bookmark-shortener/ent/user"
+	"bookmark-shortener/internal/config"
+	"bookmark-shortener/internal/database"
+	"bookmark-shortener/internal/metrics"
+	"bookmark-shortener/internal/server"
+	"bookmark-shortener/internal/tracing"
+	"bookmark-shortener/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const testSecret = "integration-test-secret-0123456789"
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testServer runs the real router against a private in-memory SQLite
+// database.
+type testServer struct {
+	t       *testing.T
+	url     string
+	http    *http.Client
+	router  *server.Router
+	client  *ent.Client
+	mailDir string
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	return newTestServerMode(t, config.AuthModeJWT)
+}
+
+// newTestServerMode is newTestServer with the given AUTH_MODE. Session mode
+// keeps sessions in memory.
+func newTestServerMode(t *testing.T, authMode string) *testServer {
+	t.Helper()
+
+	t.Setenv("DATABASE_URL", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	t.Setenv("JWT_SECRET", testSecret)
+	t.Setenv("AUTH_MODE", authMode)
+	t.Setenv("SESSION_STORE", "memory")
+	t.Setenv("CLICK_SALT", "integration-test-salt-0123456789ab")
+	// Flushes happen explicitly in the tests
+	t.Setenv("VISIT_FLUSH_INTERVAL", "1h")
+	mailDir := t.TempDir()
+	t.Setenv("MAILER", "file")
+	t.Setenv("MAIL_DIR", mailDir)
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, dialectName, err := cfg.OpenDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	drv := tracing.Driver(metrics.Driver(database.Driver(db, dialectName)), dialectName)
+	client := enttest.NewClient(t, enttest.WithOptions(ent.Driver(drv)))
+	t.Cleanup(func() { client.Close() })
+
+	router, err := server.NewRouter(cfg, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { router.Close(context.Background()) })
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	return &testServer{
+		t:   t,
+		url: srv.URL,
+		http: &http.Client{
+			// Redirect responses are under test, never follow them
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		router:  router,
+		client:  client,
+		mailDir: mailDir,
+	}
+}
+
+// do sends a request and decodes a JSON response body into out, if given.
+func (s *testServer) do(method, path, token string, body any, out any) *http.Response {
+	s.t.Helper()
+
+	return s.doWithHeaders(method, path, token, nil, body, out)
+}
+
+// doWithHeaders is do with extra request headers.
+func (s *testServer) doWithHeaders(method, path, token string, headers map[string]string, body any, out any) *http.Response {
+	s.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, s.url+path, reader)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			s.t.Fatalf("%s %s: decoding %q: %v", method, path, data, err)
+		}
+	}
+	return resp
+}
+
+func (s *testServer) expect(method, path, token string, body any, status int, out any) *http.Response {
+	s.t.Helper()
+
+	resp := s.do(method, path, token, body, out)
+	if resp.StatusCode != status {
+		s.t.Fatalf("%s %s: status %d, want %d", method, path, resp.StatusCode, status)
+	}
+	return resp
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type bookmarkResponse struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	ShortCode  string `json:"short_code"`
+	ShortURL   string `json:"short_url"`
+	VisitCount int    `json:"visit_count"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// login registers email and returns an access token for it.
+func (s *testServer) login(email string) string {
+	s.t.Helper()
+	return s.tokens(email).AccessToken
+}
+
+// tokens registers email and returns the token pair of its first login.
+func (s *testServer) tokens(email string) tokenResponse {
+	s.t.Helper()
+
+	creds := map[string]string{"email": email, "password": "password123"}
+	s.expect(http.MethodPost, "/auth/register", "", creds, http.StatusCreated, nil)
+
+	var tokens tokenResponse
+	s.expect(http.MethodPost, "/auth/token", "", creds, http.StatusOK, &tokens)
+	if tokens.AccessToken == "" {
+		s.t.Fatal("login returned no access token")
+	}
+	return tokens
+}
+
+// mail waits for the server to have sent n emails with subject and returns
+// them.
+func (s *testServer) mail(subject string, n int) []string {
+	s.t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		files, err := filepath.Glob(filepath.Join(s.mailDir, "*.eml"))
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		var messages []string
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				s.t.Fatal(err)
+			}
+			if strings.Contains(string(data), "\r\nSubject: "+subject+"\r\n") {
+				messages = append(messages, string(data))
+			}
+		}
+		if len(messages) >= n {
+			return messages
+		}
+		if time.Now().After(deadline) {
+			s.t.Fatalf("%d emails with subject %q sent, want %d", len(messages), subject, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// resetToken returns the token in a password reset email.
+func resetToken(t *testing.T, message string) string {
+	t.Helper()
+
+	_, rest, _ := strings.Cut(message, "token:\r\n\r\n")
+	token := strings.TrimSpace(strings.SplitN(rest, "\r\n", 2)[0])
+	if token == "" {
+		t.Fatalf("no token in %q", message)
+	}
+	return token
+}
+
+func (s *testServer) createBookmark(token, title, url string) bookmarkResponse {
+	s.t.Helper()
+
+	var b bookmarkResponse
+	s.expect(http.MethodPost, "/bookmarks/create", token, map[string]string{"title": title, "url": url}, http.StatusCreated, &b)
+	return b
+}
+
+func TestRegister(t *testing.T) {
+	s := newTestServer(t)
+
+	var user struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+	}
+	creds := map[string]string{"email": "alice@example.com", "password": "password123"}
+	s.expect(http.MethodPost, "/auth/register", "", creds, http.StatusCreated, &user)
+	if user.ID == "" || user.Email != "alice@example.com" {
+		t.Fatalf("unexpected user %+v", user)
+	}
+
+	var errResp errorResponse
+	s.expect(http.MethodPost, "/auth/register", "", creds, http.StatusConflict, &errResp)
+	if errResp.Error != "User already exists" {
+		t.Fatalf("error = %q", errResp.Error)
+	}
+
+	invalid := []map[string]string{
+		{"email": "not-an-email", "password": "password123"},
+		{"email": "bob@example.com", "password": "short"},
+		{"email": "bob@example.com"},
+	}
+	for _, body := range invalid {
+		s.expect(http.MethodPost, "/auth/register", "", body, http.StatusBadRequest, nil)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	s := newTestServer(t)
+	s.login("alice@example.com")
+
+	tests := []struct {
+		name   string
+		body   map[string]string
+		status int
+	}{
+		{"valid", map[string]string{"email": "alice@example.com", "password": "password123"}, http.StatusOK},
+		{"wrong password", map[string]string{"email": "alice@example.com", "password": "wrong-password"}, http.StatusUnauthorized},
+		{"unknown user", map[string]string{"email": "nobody@example.com", "password": "password123"}, http.StatusUnauthorized},
+		{"missing password", map[string]string{"email": "alice@example.com"}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tokens tokenResponse
+			s.expect(http.MethodPost, "/auth/token", "", tt.body, tt.status, &tokens)
+			if tt.status == http.StatusOK && (tokens.AccessToken == "" || tokens.RefreshToken == "") {
+				t.Fatalf("missing tokens in %+v", tokens)
+			}
+		})
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	t.Setenv("LOGIN_BACKOFF_AFTER", "3")
+	t.Setenv("LOGIN_MAX_FAILURES", "3")
+	t.Setenv("LOGIN_LOCKOUT", "1h")
+	t.Setenv("RATE_LIMIT_AUTH", "off")
+	s := newTestServer(t)
+	s.login("alice@example.com")
+	s.login("bob@example.com")
+
+	// Failures of existing accounts are recorded after the response
+	fail := func(email string) {
+		t.Helper()
+		creds := map[string]string{"email": email, "password": "wrong-password"}
+		s.expect(http.MethodPost, "/auth/token", "", creds, http.StatusUnauthorized, nil)
+		if err := s.router.Flush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	right := func(email string) map[string]string {
+		return map[string]string{"email": email, "password": "password123"}
+	}
+
+	// A success resets the count
+	for range 2 {
+		fail("bob@example.com")
+	}
+	s.expect(http.MethodPost, "/auth/token", "", right("bob@example.com"), http.StatusOK, nil)
+	fail("bob@example.com")
+
+	// Known and unknown emails lock alike, even with the right password
+	for _, email := range []string{"alice@example.com", "nobody@example.com"} {
+		for range 3 {
+			fail(email)
+		}
+		var body errorResponse
+		resp := s.expect(http.MethodPost, "/auth/token", "", right(email), http.StatusTooManyRequests, &body)
+		if resp.Header.Get("Retry-After") == "" || body.Error != "Too many failed login attempts, try again later" {
+			t.Fatalf("%s: Retry-After %q, error %q", email, resp.Header.Get("Retry-After"), body.Error)
+		}
+	}
+
+	alice, err := s.client.User.Query().Where(user.Email("alice@example.com")).Only(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.FailedLoginAttempts != 3 || alice.LockedUntil == nil || time.Until(*alice.LockedUntil) < 59*time.Minute {
+		t.Fatalf("alice: %d failed attempts, locked until %v", alice.FailedLoginAttempts, alice.LockedUntil)
+	}
+	bob, err := s.client.User.Query().Where(user.Email("bob@example.com")).Only(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bob.FailedLoginAttempts != 1 || bob.LockedUntil != nil {
+		t.Fatalf("bob: %d failed attempts, locked until %v", bob.FailedLoginAttempts, bob.LockedUntil)
+	}
+}
+
+func TestLoginLockoutPerIP(t *testing.T) {
+	t.Setenv("LOGIN_MAX_FAILURES_PER_IP", "2")
+	t.Setenv("RATE_LIMIT_AUTH", "off")
+	s := newTestServer(t)
+	s.login("alice@example.com")
+
+	for _, email := range []string{"bob@example.com", "carol@example.com"} {
+		s.expect(http.MethodPost, "/auth/token", "", map[string]string{"email": email, "password": "guess"}, http.StatusUnauthorized, nil)
+	}
+	creds := map[string]string{"email": "alice@example.com", "password": "password123"}
+	s.expect(http.MethodPost, "/auth/token", "", creds, http.StatusTooManyRequests, nil)
+}
+
+func TestPasswordReset(t *testing.T) {
+	t.Setenv("RATE_LIMIT_AUTH", "off")
+	s := newTestServer(t)
+	s.login("alice@example.com")
+	var old tokenResponse
+	s.expect(http.MethodPost, "/auth/token", "", map[string]string{"email": "alice@example.com", "password": "password123"}, http.StatusOK, &old)
+
+	// Unknown emails get the same answer, but no email
+	s.expect(http.MethodPost, "/auth/password/forgot", "", map[string]string{"email": "nobody@example.com"}, http.StatusAccepted, nil)
+	s.expect(http.MethodPost, "/auth/password/forgot", "", map[string]string{"email": "alice@example.com"}, http.StatusAccepted, nil)
+	// Asking again right away doesn't send another
+	s.expect(http.MethodPost, "/auth/password/forgot", "", map[string]string{"email": "alice@example.com"}, http.StatusAccepted, nil)
+
+	if err := s.router.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	messages := s.mail("Reset your password", 1)
+	if len(messages) != 1 || !strings.Contains(messages[0], "To: alice@example.com\r\n") {
+		t.Fatalf("sent %q", messages)
+	}
+	token := resetToken(t, messages[0])
+
+	// Only the hash is stored
+	stored, err := s.client.PasswordResetToken.Query().Only(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.TokenHash != utils.HashToken(token) {
+		t.Fatal("stored token is not the token's hash")
+	}
+
+	reset := map[string]string{"token": token, "password": "new-password"}
+	s.expect(http.MethodPost, "/auth/password/reset", "", map[string]string{"token": "guess", "password": "new-password"}, http.StatusBadRequest, nil)
+	s.expect(http.MethodPost, "/auth/password/reset", "", map[string]string{"token": token, "password": "short"}, http.StatusBadRequest, nil)
+	s.expect(http.MethodPost, "/auth/password/reset", "", reset, http.StatusOK, nil)
+
+	// The token works once
+	var body errorResponse
+	s.expect(http.MethodPost, "/auth/password/reset", "", reset, http.StatusBadRequest, &body)
+	if body.Error != "Invalid or expired reset token" {
+		t.Fatalf("reused token: %q", body.Error)
+	}
+
+	// Tokens issued before the reset no longer work
+	s.expect(http.MethodGet, "/bookmarks/get", old.AccessToken, nil, http.StatusUnauthorized, nil)
+	s.expect(http.MethodPost, "/auth/refresh", "", map[string]string{"refresh_token": old.RefreshToken}, http.StatusUnauthorized, nil)
+
+	s.expect(http.MethodPost, "/auth/token", "", map[string]string{"email": "alice@example.com", "password": "password123"}, http.StatusUnauthorized, nil)
+	s.expect(http.MethodPost, "/auth/token", "", map[string]string{"email": "alice@example.com", "password": "new-password"}, http.StatusOK, nil)
+}
+
+func TestPasswordResetConcurrent(t *testing.T) {
+	t.Setenv("RATE_LIMIT_AUTH", "off")
+	s := newTestServer(t)
+	s.login("alice@example.com")
+	s.login("bob@example.com")
+	// Hold every resend check until the others have run, so each send
+	// finds no recent token unless sends for one account are deduplicated
+	s.client.PasswordResetToken.Intercept(ent.InterceptFunc(func(next ent.Querier) ent.Querier {
+		return ent.QuerierFunc(func(ctx context.Context, q ent.Query) (ent.Value, error) {
+			v, err := next.Query(ctx, q)
+			time.Sleep(50 * time.Millisecond)
+			return v, err
+		})
+	}))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		for _, email := range []string{"alice@example.com", "bob@example.com"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := s.http.Post(s.url+"/auth/password/forgot", "application/json", strings.NewReader(`{"email": "`+email+`"}`))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				resp.Body.Close()
+			}()
+		}
+	}
+	wg.Wait()
+	if err := s.router.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := s.mail("Reset your password", 2)
+	if len(messages) != 2 {
+		t.Fatalf("sent %d emails, want one per account", len(messages))
+	}
+	for _, email := range []string{"alice@example.com", "bob@example.com"} {
+		if !strings.Contains(strings.Join(messages, ""), "To: "+email+"\r\n") {
+			t.Fatalf("no email to %s", email)
+		}
+	}
+}
+
+func TestPasswordResetExpiry(t *testing.T) {
+	s := newTestServer(t)
+	s.login("alice@example.com")
+
+	alice, err := s.client.User.Query().Where(user.Email("alice@example.com")).Only(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := "expired-reset-token"
+	_, err = s.client.PasswordResetToken.Create().
+		SetTokenHash(utils.HashToken(token)).
+		SetExpiresAt(time.Now().Add(-time.Second)).
+		SetUser(alice).
+		Save(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.expect(http.MethodPost, "/auth/password/reset", "", map[string]string{"token": token, "password": "new-password"}, http.StatusBadRequest, nil)
+	unused, err := s.client.PasswordResetToken.Query().Where(passwordresettoken.UsedAtIsNil()).Exist(context.Background())
+	if err != nil || !unused {
+		t.Fatalf("expired token was consumed: %v", err)
+	}
+	s.expect(http.MethodPost, "/auth/token", "", map[string]string{"email": "alice@example.com", "password": "password123"}, http.StatusOK, nil)
+}
+
+func TestEmailVerification(t *testing.T) {
+	t.Setenv("REQUIRE_VERIFIED_EMAIL", "true")
+	t.Setenv("RATE_LIMIT_AUTH", "off")
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+
+	bookmark := map[string]string{"title": "Example", "url": "https://example.com"}
+	var body errorResponse
+	s.expect(http.MethodPost, "/bookmarks/create", token, bookmark, http.StatusForbidden, &body)
+	if body.Error != "Verify your email address first" {
+		t.Fatalf("unverified create: %q", body.Error)
+	}
+
+	messages := s.mail("Verify your email address", 1)
+	if !strings.Contains(messages[0], "To: alice@example.com\r\n") {
+		t.Fatalf("sent %q", messages[0])
+	}
+	_, link, _ := strings.Cut(messages[0], "http://127.0.0.1:8080")
+	link = strings.SplitN(link, "\r\n", 2)[0]
+	if !strings.HasPrefix(link, "/auth/verify?token=") {
+		t.Fatalf("no verification link in %q", messages[0])
+	}
+
+	s.expect(http.MethodGet, "/auth/verify?token=forged", "", nil, http.StatusBadRequest, nil)
+	s.expect(http.MethodGet, link+"x", "", nil, http.StatusBadRequest, nil)
+	s.expect(http.MethodGet, link, "", nil, http.StatusOK, nil)
+	// Links can be followed again
+	s.expect(http.MethodGet, link, "", nil, http.StatusOK, nil)
+
+	alice, err := s.client.User.Query().Where(user.Email("alice@example.com")).Only(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.EmailVerifiedAt == nil {
+		t.Fatal("email_verified_at is not set")
+	}
+	s.expect(http.MethodPost, "/bookmarks/create", token, bookmark, http.StatusCreated, nil)
+	s.expect(http.MethodPost, "/auth/verify/resend", token, nil, http.StatusConflict, nil)
+
+	// A link only verifies the address it was sent to
+	bob := s.login("bob@example.com")
+	s.expect(http.MethodPost, "/auth/verify/resend", bob, nil, http.StatusAccepted, nil)
+	messages = s.mail("Verify your email address", 3)
+	if err := s.client.User.Update().Where(user.Email("bob@example.com")).SetEmail("bob@example.org").Exec(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range messages {
+		if !strings.Contains(message, "To: bob@example.com\r\n") {
+			continue
+		}
+		_, link, _ := strings.Cut(message, "http://127.0.0.1:8080")
+		s.expect(http.MethodGet, strings.SplitN(link, "\r\n", 2)[0], "", nil, http.StatusBadRequest, nil)
+	}
+}
+
+func TestTwoFactor(t *testing.T) {
+	t.Setenv("TOTP_ENCRYPTION_KEY", strings.Repeat("5e", 32))
+	t.Setenv("RATE_LIMIT_AUTH", "off")
+	t.Setenv("LOGIN_BACKOFF_AFTER", "10")
+	t.Setenv("LOGIN_MAX_FAILURES", "10")
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+	creds := map[string]string{"email": "alice@example.com", "password": "password123"}
+
+	var setup struct {
+		Secret     string `json:"secret"`
+		OtpauthURI string `json:"otpauth_uri"`
+	}
+	s.expect(http.MethodPost, "/auth/2fa/setup", token, nil, http.StatusOK, &setup)
+	if setup.Secret == "" || !strings.HasPrefix(setup.OtpauthURI, "otpauth://totp/") {
+		t.Fatalf("setup returned %+v", setup)
+	}
+	alice, err := s.client.User.Query().Where(user.Email("alice@example.com")).Only(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.TotpSecret == nil || strings.Contains(*alice.TotpSecret, setup.Secret) {
+		t.Fatal("TOTP secret is not stored encrypted")
+	}
+
+	// Logins stay password-only until a code is confirmed
+	var tokens tokenResponse
+	s.expect(http.MethodPost, "/auth/token", "", creds, http.StatusOK, &tokens)
+	if tokens.AccessToken == "" {
+		t.Fatal("pending setup required a second factor")
+	}
+
+	code := func(at time.Time) string {
+		code, err := totp.GenerateCode(setup.Secret, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+	s.expect(http.MethodPost, "/auth/2fa/confirm", token, map[string]string{"code": "000000"}, http.StatusBadRequest, nil)
+	var confirmed struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	confirmedAt := time.Now()
+	s.expect(http.MethodPost, "/auth/2fa/confirm", token, map[string]string{"code": code(confirmedAt)}, http.StatusOK, &confirmed)
+	if len(confirmed.RecoveryCodes) != 10 {
+		t.Fatalf("%d recovery codes", len(confirmed.RecoveryCodes))
+	}
+	s.expect(http.MethodPost, "/auth/2fa/setup", token, nil, http.StatusConflict, nil)
+
+	challenge := func() string {
+		var resp struct {
+			MFARequired bool   `json:"mfa_required"`
+			MFAToken    string `json:"mfa_token"`
+			AccessToken string `json:"access_token"`
+		}
+		s.expect(http.MethodPost, "/auth/token", "", creds, http.StatusOK, &resp)
+		if !resp.MFARequired || resp.MFAToken == "" || resp.AccessToken != "" {
+			t.Fatalf("login returned %+v", resp)
+		}
+		return resp.MFAToken
+	}
+	verify := func(body map[string]string, status int) tokenResponse {
+		t.Helper()
+		var tokens tokenResponse
+		s.expect(http.MethodPost, "/auth/2fa/verify", "", body, status, &tokens)
+		return tokens
+	}
+
+	mfaToken := challenge()
+	verify(map[string]string{"mfa_token": "forged", "code": code(time.Now())}, http.StatusUnauthorized)
+	verify(map[string]string{"mfa_token": token, "code": code(time.Now())}, http.StatusUnauthorized)
+	verify(map[string]string{"mfa_token": mfaToken}, http.StatusBadRequest)
+	// The code used to confirm can't be replayed
+	verify(map[string]string{"mfa_token": mfaToken, "code": code(confirmedAt)}, http.StatusUnauthorized)
+
+	next := code(confirmedAt.Add(30 * time.Second))
+	tokens = verify(map[string]string{"mfa_token": mfaToken, "code": next}, http.StatusOK)
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Fatalf("missing tokens in %+v", tokens)
+	}
+	s.expect(http.MethodGet, "/bookmarks/get", tokens.AccessToken, nil, http.StatusOK, nil)
+	verify(map[string]string{"mfa_token": challenge(), "code": next}, http.StatusUnauthorized)
+
+	// Recovery codes work once, however they are typed
+	recovery := strings.ToUpper(strings.ReplaceAll(confirmed.RecoveryCodes[0], "-", ""))
+	verify(map[string]string{"mfa_token": challenge(), "recovery_code": recovery}, http.StatusOK)
+	verify(map[string]string{"mfa_token": challenge(), "recovery_code": recovery}, http.StatusUnauthorized)
+
+	// Wrong codes count towards the account lockout, here the reused
+	// recovery code since the last successful login
+	alice, err = s.client.User.Query().Where(user.Email("alice@example.com")).Only(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.FailedLoginAttempts != 1 {
+		t.Fatalf("%d failed attempts, want 1", alice.FailedLoginAttempts)
+	}
+}
+
+func TestTwoFactorNotConfigured(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+	s.expect(http.MethodPost, "/auth/2fa/setup", token, nil, http.StatusServiceUnavailable, nil)
+}
+
+func TestTokenFailures(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+
+	claims, err := utils.ValidateToken(token, []byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(claims utils.Claims, secret string) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	expired := *claims
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	noJTI := *claims
+	noJTI.ID = ""
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"missing header", ""},
+		{"wrong scheme", "Basic " + token},
+		{"garbage", "Bearer not-a-jwt"},
+		{"expired", "Bearer " + sign(expired, testSecret)},
+		{"wrong secret", "Bearer " + sign(*claims, "some-other-secret")},
+		{"missing jti", "Bearer " + sign(noJTI, testSecret)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, s.url+"/bookmarks/get", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp, err := s.http.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusUnauthorized {
+				t.Fatalf("status %d, want 401", resp.StatusCode)
+			}
+		})
+	}
+
+	t.Run("revoked after logout", func(t *testing.T) {
+		s.expect(http.MethodGet, "/bookmarks/get", token, nil, http.StatusOK, nil)
+		s.expect(http.MethodPost, "/auth/logout", token, nil, http.StatusOK, nil)
+		s.expect(http.MethodGet, "/bookmarks/get", token, nil, http.StatusUnauthorized, nil)
+	})
+}
+
+func TestRefreshRotation(t *testing.T) {
+	s := newTestServer(t)
+	first := s.tokens("alice@example.com")
+
+	var second tokenResponse
+	s.expect(http.MethodPost, "/auth/refresh", "", map[string]string{"refresh_token": first.RefreshToken}, http.StatusOK, &second)
+	if second.AccessToken == "" || second.RefreshToken == "" || second.RefreshToken == first.RefreshToken {
+		t.Fatalf("refresh returned %+v, want a new pair", second)
+	}
+	s.expect(http.MethodGet, "/bookmarks/get", second.AccessToken, nil, http.StatusOK, nil)
+
+	// The new refresh token rotates again; the old one is spent
+	var third tokenResponse
+	s.expect(http.MethodPost, "/auth/refresh", "", map[string]string{"refresh_token": second.RefreshToken}, http.StatusOK, &third)
+	s.expect(http.MethodPost, "/auth/refresh", "", map[string]string{"refresh_token": "not-a-token"}, http.StatusUnauthorized, nil)
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	s := newTestServer(t)
+	first := s.tokens("alice@example.com")
+	other := s.tokens("bob@example.com")
+
+	var second tokenResponse
+	s.expect(http.MethodPost, "/auth/refresh", "", map[string]string{"refresh_token": first.RefreshToken}, http.StatusOK, &second)
+
+	var body errorResponse
+	s.expect(http.MethodPost, "/auth/refresh", "", map[string]string{"refresh_token": first.RefreshToken}, http.StatusUnauthorized, &body)
+	if body.Error != "Refresh token reuse detected" {
+		t.Fatalf("error = %q", body.Error)
+	}
+
+	// Whoever holds the newer pair is logged out too
+	s.expect(http.MethodGet, "/bookmarks/get", second.AccessToken, nil, http.StatusUnauthorized, nil)
+	s.expect(http.MethodPost, "/auth/refresh", "", map[string]string{"refresh_token": second.RefreshToken}, http.StatusUnauthorized, nil)
+	s.expect(http.MethodGet, "/bookmarks/get", first.AccessToken, nil, http.StatusUnauthorized, nil)
+
+	// Other families are untouched
+	s.expect(http.MethodGet, "/bookmarks/get", other.AccessToken, nil, http.StatusOK, nil)
+	s.expect(http.MethodPost, "/auth/refresh", "", map[string]string{"refresh_token": other.RefreshToken}, http.StatusOK, nil)
+}
+
+func TestRefreshConcurrent(t *testing.T) {
+	t.Setenv("RATE_LIMIT_AUTH", "off")
+	s := newTestServer(t)
+	first := s.tokens("alice@example.com")
+
+	body := `{"refresh_token": "` + first.RefreshToken + `"}`
+	const requests = 10
+	statuses := make(chan int, requests)
+	var wg sync.WaitGroup
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := s.http.Post(s.url+"/auth/refresh", "application/json", strings.NewReader(body))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	counts := map[int]int{}
+	for status := range statuses {
+		counts[status]++
+	}
+	if counts[http.StatusOK] != 1 || counts[http.StatusUnauthorized] != requests-1 {
+		t.Fatalf("got statuses %v, want 1 success and %d rejected", counts, requests-1)
+	}
+}
+
+func TestSessionAuth(t *testing.T) {
+	t.Setenv("RATE_LIMIT_AUTH", "off")
+	s := newTestServerMode(t, config.AuthModeSession)
+	first := s.tokens("alice@example.com")
+	if first.RefreshToken != "" {
+		t.Fatal("session login returned a refresh token")
+	}
+	var second tokenResponse
+	s.expect(http.MethodPost, "/auth/token", "", map[string]string{"email": "alice@example.com", "password": "password123"}, http.StatusOK, &second)
+	if second.AccessToken == first.AccessToken {
+		t.Fatal("logins share a session")
+	}
+
+	s.createBookmark(first.AccessToken, "Go", "https://go.dev")
+	var list []bookmarkResponse
+	s.expect(http.MethodGet, "/bookmarks/get", second.AccessToken, nil, http.StatusOK, &list)
+	if len(list) != 1 {
+		t.Fatalf("listed %d bookmarks, want the one created in the other session", len(list))
+	}
+
+	var body errorResponse
+	s.expect(http.MethodGet, "/bookmarks/get", "not-a-session", nil, http.StatusUnauthorized, &body)
+	if body.Error != "Invalid session" {
+		t.Fatalf("error = %q", body.Error)
+	}
+	s.expect(http.MethodPost, "/auth/refresh", "", map[string]string{"refresh_token": first.AccessToken}, http.StatusBadRequest, &body)
+	if body.Error != "Sessions are renewed on use and cannot be refreshed" {
+		t.Fatalf("error = %q", body.Error)
+	}
+
+	// Logging out ends only the session used
+	s.expect(http.MethodPost, "/auth/logout", first.AccessToken, nil, http.StatusOK, nil)
+	s.expect(http.MethodGet, "/bookmarks/get", first.AccessToken, nil, http.StatusUnauthorized, nil)
+	s.expect(http.MethodPost, "/auth/logout", first.AccessToken, nil, http.StatusUnauthorized, nil)
+	s.expect(http.MethodGet, "/bookmarks/get", second.AccessToken, nil, http.StatusOK, nil)
+}
+
+func TestSessionPasswordReset(t *testing.T) {
+	t.Setenv("RATE_LIMIT_AUTH", "off")
+	s := newTestServerMode(t, config.AuthModeSession)
+	alice := s.login("alice@example.com")
+	bob := s.login("bob@example.com")
+	var other tokenResponse
+	s.expect(http.MethodPost, "/auth/token", "", map[string]string{"email": "alice@example.com", "password": "password123"}, http.StatusOK, &other)
+
+	s.expect(http.MethodPost, "/auth/password/forgot", "", map[string]string{"email": "alice@example.com"}, http.StatusAccepted, nil)
+	token := resetToken(t, s.mail("Reset your password", 1)[0])
+	s.expect(http.MethodPost, "/auth/password/reset", "", map[string]string{"token": token, "password": "new-password"}, http.StatusOK, nil)
+
+	// Every session of the user ends, other users' stay
+	s.expect(http.MethodGet, "/bookmarks/get", alice, nil, http.StatusUnauthorized, nil)
+	s.expect(http.MethodGet, "/bookmarks/get", other.AccessToken, nil, http.StatusUnauthorized, nil)
+	s.expect(http.MethodGet, "/bookmarks/get", bob, nil, http.StatusOK, nil)
+
+	var fresh tokenResponse
+	s.expect(http.MethodPost, "/auth/token", "", map[string]string{"email": "alice@example.com", "password": "new-password"}, http.StatusOK, &fresh)
+	s.expect(http.MethodGet, "/bookmarks/get", fresh.AccessToken, nil, http.StatusOK, nil)
+}
+
+func TestBookmarkCRUD(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+
+	created := s.createBookmark(token, "Go", "https://go.dev")
+	if created.ShortCode == "" || !strings.HasSuffix(created.ShortURL, "/"+created.ShortCode) {
+		t.Fatalf("unexpected bookmark %+v", created)
+	}
+
+	var got bookmarkResponse
+	resp := s.expect(http.MethodGet, "/bookmarks/get/"+created.ID, token, nil, http.StatusOK, &got)
+	if got != created {
+		t.Fatalf("got %+v, want %+v", got, created)
+	}
+	if resp.Header.Get("ETag") == "" {
+		t.Fatal("missing ETag")
+	}
+
+	var list []bookmarkResponse
+	s.expect(http.MethodGet, "/bookmarks/get", token, nil, http.StatusOK, &list)
+	if len(list) != 1 || list[0].ID != created.ID {
+		t.Fatalf("unexpected list %+v", list)
+	}
+
+	var updated bookmarkResponse
+	s.expect(http.MethodPatch, "/bookmarks/"+created.ID, token, map[string]string{"title": "The Go Programming Language"}, http.StatusOK, &updated)
+	if updated.Title != "The Go Programming Language" || updated.URL != created.URL {
+		t.Fatalf("unexpected update %+v", updated)
+	}
+
+	s.expect(http.MethodPost, "/bookmarks/create", token, map[string]string{"title": "No URL"}, http.StatusBadRequest, nil)
+	s.expect(http.MethodGet, "/bookmarks/get/not-a-uuid", token, nil, http.StatusBadRequest, nil)
+
+	s.expect(http.MethodDelete, "/bookmarks/delete/"+created.ID, token, nil, http.StatusOK, nil)
+	s.expect(http.MethodGet, "/bookmarks/get/"+created.ID, token, nil, http.StatusNotFound, nil)
+	s.expect(http.MethodDelete, "/bookmarks/delete/"+created.ID, token, nil, http.StatusNotFound, nil)
+}
+
+func TestOwnerIsolation(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.login("alice@example.com")
+	bob := s.login("bob@example.com")
+
+	b := s.createBookmark(alice, "Go", "https://go.dev")
+
+	s.expect(http.MethodGet, "/bookmarks/get/"+b.ID, bob, nil, http.StatusNotFound, nil)
+	s.expect(http.MethodPatch, "/bookmarks/"+b.ID, bob, map[string]string{"title": "Mine now"}, http.StatusNotFound, nil)
+	s.expect(http.MethodDelete, "/bookmarks/delete/"+b.ID, bob, nil, http.StatusNotFound, nil)
+
+	var list []bookmarkResponse
+	s.expect(http.MethodGet, "/bookmarks/get", bob, nil, http.StatusOK, &list)
+	if len(list) != 0 {
+		t.Fatalf("bob sees %d bookmarks", len(list))
+	}
+
+	var got bookmarkResponse
+	s.expect(http.MethodGet, "/bookmarks/get/"+b.ID, alice, nil, http.StatusOK, &got)
+	if got.Title != "Go" {
+		t.Fatalf("title = %q", got.Title)
+	}
+}
+
+func TestDuplicateURL(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+
+	first := s.createBookmark(token, "Go", "https://go.dev")
+	second := s.createBookmark(token, "Blog", "https://go.dev/blog")
+
+	var errResp errorResponse
+	s.expect(http.MethodPost, "/bookmarks/create", token, map[string]string{"title": "Again", "url": "https://go.dev"}, http.StatusConflict, &errResp)
+	if errResp.Error != "Bookmark already exists" {
+		t.Fatalf("error = %q", errResp.Error)
+	}
+
+	s.expect(http.MethodPatch, "/bookmarks/"+second.ID, token, map[string]string{"url": first.URL}, http.StatusConflict, nil)
+}
+
+func TestListPagination(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+
+	titles := []string{"a", "b", "c", "d", "e"}
+	for _, title := range titles {
+		s.createBookmark(token, title, "https://example.com/"+title)
+	}
+
+	t.Run("follows cursors", func(t *testing.T) {
+		var got []string
+		path := "/bookmarks/get?limit=2&sort=title&order=asc"
+		for pages := 0; path != ""; pages++ {
+			if pages == len(titles) {
+				t.Fatal("pagination does not end")
+			}
+			var page []bookmarkResponse
+			resp := s.expect(http.MethodGet, path, token, nil, http.StatusOK, &page)
+			for _, b := range page {
+				got = append(got, b.Title)
+			}
+
+			path = ""
+			if cursor := resp.Header.Get("X-Next-Cursor"); cursor != "" {
+				link := resp.Header.Get("Link")
+				if !strings.Contains(link, "cursor="+cursor) || !strings.HasSuffix(link, `>; rel="next"`) {
+					t.Fatalf("Link = %q for cursor %q", link, cursor)
+				}
+				path = "/bookmarks/get?limit=2&sort=title&order=asc&cursor=" + cursor
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(titles, ",") {
+			t.Fatalf("got %v, want %v", got, titles)
+		}
+	})
+
+	t.Run("rejects foreign cursors", func(t *testing.T) {
+		resp := s.expect(http.MethodGet, "/bookmarks/get?limit=2&sort=title&order=asc", token, nil, http.StatusOK, nil)
+		cursor := resp.Header.Get("X-Next-Cursor")
+		if cursor == "" {
+			t.Fatal("missing X-Next-Cursor")
+		}
+		s.expect(http.MethodGet, "/bookmarks/get?limit=2&sort=title&order=desc&cursor="+cursor, token, nil, http.StatusBadRequest, nil)
+		s.expect(http.MethodGet, "/bookmarks/get?limit=2&sort=created_at&order=asc&cursor="+cursor, token, nil, http.StatusBadRequest, nil)
+		s.expect(http.MethodGet, "/bookmarks/get?cursor=not-a-cursor", token, nil, http.StatusBadRequest, nil)
+		s.expect(http.MethodGet, "/bookmarks/get?limit=500", token, nil, http.StatusBadRequest, nil)
+	})
+
+	t.Run("filters by domain", func(t *testing.T) {
+		other := s.createBookmark(token, "Go", "https://Go.dev/doc")
+		var page []bookmarkResponse
+		s.expect(http.MethodGet, "/bookmarks/get?domain=GO.DEV", token, nil, http.StatusOK, &page)
+		if len(page) != 1 || page[0].ID != other.ID {
+			t.Fatalf("unexpected page %+v", page)
+		}
+		s.expect(http.MethodGet, "/bookmarks/get?domain=go.dev.example", token, nil, http.StatusOK, &page)
+		if len(page) != 0 {
+			t.Fatalf("unexpected page %+v", page)
+		}
+	})
+}
+
+func TestAlias(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+
+	var b bookmarkResponse
+	s.expect(http.MethodPost, "/bookmarks/create", token, map[string]string{"title": "Go", "url": "https://go.dev", "alias": "golang"}, http.StatusCreated, &b)
+	if b.ShortCode != "golang" {
+		t.Fatalf("short_code = %q", b.ShortCode)
+	}
+	s.expect(http.MethodGet, "/golang", "", nil, http.StatusFound, nil)
+
+	var errResp errorResponse
+	s.expect(http.MethodPost, "/bookmarks/create", token, map[string]string{"title": "Blog", "url": "https://go.dev/blog", "alias": "golang"}, http.StatusConflict, &errResp)
+	if errResp.Error != "Alias already taken" {
+		t.Fatalf("error = %q", errResp.Error)
+	}
+
+	for _, alias := range []string{"auth", "Bookmarks", "metrics", "ab", "no spaces"} {
+		s.expect(http.MethodPost, "/bookmarks/create", token, map[string]string{"title": "Blog", "url": "https://go.dev/blog", "alias": alias}, http.StatusBadRequest, nil)
+	}
+}
+
+func TestStats(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+	b := s.createBookmark(token, "Go", "https://go.dev")
+
+	clicks := []struct{ referrer, userAgent string }{
+		{"https://news.example/item", "Firefox"},
+		{"https://news.example/other", "Chrome"},
+		{"https://blog.example/", "Firefox"},
+		{"", "Firefox"},
+	}
+	for _, click := range clicks {
+		headers := map[string]string{"User-Agent": click.userAgent}
+		if click.referrer != "" {
+			headers["Referer"] = click.referrer
+		}
+		resp := s.doWithHeaders(http.MethodGet, "/"+b.ShortCode, "", headers, nil, nil)
+		if resp.StatusCode != http.StatusFound {
+			t.Fatalf("redirect status %d", resp.StatusCode)
+		}
+	}
+	if err := s.router.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var stats struct {
+		Bucket         string `json:"bucket"`
+		TotalClicks    int    `json:"total_clicks"`
+		UniqueVisitors int    `json:"unique_visitors"`
+		Series         []struct {
+			Start          time.Time `json:"start"`
+			Clicks         int       `json:"clicks"`
+			UniqueVisitors int       `json:"unique_visitors"`
+		} `json:"series"`
+		TopReferrers []struct {
+			Host   string `json:"host"`
+			Clicks int    `json:"clicks"`
+		} `json:"top_referrers"`
+	}
+	s.expect(http.MethodGet, "/bookmarks/get/"+b.ID+"/stats?bucket=hour", token, nil, http.StatusOK, &stats)
+	if stats.Bucket != "hour" || stats.TotalClicks != len(clicks) || stats.UniqueVisitors != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	last := stats.Series[len(stats.Series)-1]
+	if len(stats.Series) != 31 || last.Clicks != len(clicks) || last.UniqueVisitors != 2 || !last.Start.Equal(time.Now().UTC().Truncate(time.Hour)) {
+		t.Fatalf("unexpected series of %d buckets ending in %+v", len(stats.Series), last)
+	}
+	referrers := fmt.Sprint(stats.TopReferrers)
+	if referrers != "[{news.example 2} {blog.example 1}]" {
+		t.Fatalf("top_referrers = %s", referrers)
+	}
+
+	// Clicks outside the range are not counted
+	from := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	to := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	s.expect(http.MethodGet, "/bookmarks/get/"+b.ID+"/stats?from="+from+"&to="+to, token, nil, http.StatusOK, &stats)
+	if stats.TotalClicks != 0 || stats.UniqueVisitors != 0 || len(stats.TopReferrers) != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	s.expect(http.MethodGet, "/bookmarks/get/"+b.ID+"/stats?bucket=week", token, nil, http.StatusBadRequest, nil)
+	s.expect(http.MethodGet, "/bookmarks/get/"+b.ID+"/stats?from="+to+"&to="+from, token, nil, http.StatusBadRequest, nil)
+	s.expect(http.MethodGet, "/bookmarks/get/"+b.ID+"/stats?bucket=hour&from=2020-01-01T00:00:00Z", token, nil, http.StatusBadRequest, nil)
+	s.expect(http.MethodGet, "/bookmarks/get/"+b.ID+"/stats", s.login("bob@example.com"), nil, http.StatusNotFound, nil)
+}
+
+func TestUpdateIfMatch(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+	b := s.createBookmark(token, "Go", "https://go.dev")
+
+	resp := s.expect(http.MethodGet, "/bookmarks/get/"+b.ID, token, nil, http.StatusOK, nil)
+	etag := resp.Header.Get("ETag")
+
+	var updated bookmarkResponse
+	resp = s.doWithHeaders(http.MethodPatch, "/bookmarks/"+b.ID, token, map[string]string{"If-Match": etag}, map[string]string{"title": "First"}, &updated)
+	if resp.StatusCode != http.StatusOK || updated.Title != "First" {
+		t.Fatalf("status %d, title %q", resp.StatusCode, updated.Title)
+	}
+	newETag := resp.Header.Get("ETag")
+	if newETag == "" || newETag == etag {
+		t.Fatalf("ETag %q after update, was %q", newETag, etag)
+	}
+
+	// A client still holding the old version must not overwrite the update
+	var errResp errorResponse
+	resp = s.doWithHeaders(http.MethodPatch, "/bookmarks/"+b.ID, token, map[string]string{"If-Match": etag}, map[string]string{"title": "Second"}, &errResp)
+	if resp.StatusCode != http.StatusPreconditionFailed || errResp.Error != "Bookmark has been modified" {
+		t.Fatalf("status %d, error %q", resp.StatusCode, errResp.Error)
+	}
+
+	var got bookmarkResponse
+	s.expect(http.MethodGet, "/bookmarks/get/"+b.ID, token, nil, http.StatusOK, &got)
+	if got.Title != "First" {
+		t.Fatalf("title = %q", got.Title)
+	}
+}
+
+func TestRedirectCacheInvalidation(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+	b := s.createBookmark(token, "Go", "https://go.dev")
+
+	location := func() string {
+		t.Helper()
+		resp := s.expect(http.MethodGet, "/"+b.ShortCode, "", nil, http.StatusFound, nil)
+		return resp.Header.Get("Location")
+	}
+
+	// The first redirect caches the short code
+	if got := location(); got != "https://go.dev" {
+		t.Fatalf("Location = %q", got)
+	}
+	s.expect(http.MethodPatch, "/bookmarks/"+b.ID, token, map[string]string{"url": "https://go.dev/doc"}, http.StatusOK, nil)
+	if got := location(); got != "https://go.dev/doc" {
+		t.Fatalf("Location = %q after update", got)
+	}
+
+	// A rejected update leaves the cached target alone
+	resp := s.doWithHeaders(http.MethodPatch, "/bookmarks/"+b.ID, token, map[string]string{"If-Match": `"stale"`}, map[string]string{"url": "https://go.dev/blog"}, nil)
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("stale update status %d", resp.StatusCode)
+	}
+	s.expect(http.MethodPatch, "/bookmarks/"+b.ID, token, map[string]string{"url": "not a url"}, http.StatusBadRequest, nil)
+	if got := location(); got != "https://go.dev/doc" {
+		t.Fatalf("Location = %q after rejected updates", got)
+	}
+
+	s.expect(http.MethodDelete, "/bookmarks/delete/"+b.ID, token, nil, http.StatusOK, nil)
+	s.expect(http.MethodGet, "/"+b.ShortCode, "", nil, http.StatusNotFound, nil)
+}
+
+func TestRedirectCounting(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+	b := s.createBookmark(token, "Go", "https://go.dev")
+
+	const visits = 3
+	for range visits {
+		resp := s.expect(http.MethodGet, "/"+b.ShortCode, "", nil, http.StatusFound, nil)
+		if loc := resp.Header.Get("Location"); loc != b.URL {
+			t.Fatalf("Location = %q, want %q", loc, b.URL)
+		}
+	}
+	s.expect(http.MethodGet, "/doesnotexist", "", nil, http.StatusNotFound, nil)
+
+	if err := s.router.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got bookmarkResponse
+	s.expect(http.MethodGet, "/bookmarks/get/"+b.ID, token, nil, http.StatusOK, &got)
+	if got.VisitCount != visits {
+		t.Fatalf("visit_count = %d, want %d", got.VisitCount, visits)
+	}
+	clicks, err := s.client.ClickEvent.Query().Count(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clicks != visits {
+		t.Fatalf("recorded %d click events, want %d", clicks, visits)
+	}
+}
+
+func TestMaxVisitsConcurrent(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+
+	const maxVisits, requests = 3, 20
+	var b bookmarkResponse
+	s.expect(http.MethodPost, "/bookmarks/create", token, map[string]any{"title": "Go", "url": "https://go.dev", "max_visits": maxVisits}, http.StatusCreated, &b)
+
+	statuses := make(chan int, requests)
+	var wg sync.WaitGroup
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := s.http.Get(s.url + "/" + b.ShortCode)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	counts := map[int]int{}
+	for status := range statuses {
+		counts[status]++
+	}
+	if counts[http.StatusFound] != maxVisits || counts[http.StatusGone] != requests-maxVisits {
+		t.Fatalf("got statuses %v, want %d redirects and %d gone", counts, maxVisits, requests-maxVisits)
+	}
+}
+
+func TestMaxVisitsUpdateFails(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+
+	var limited, unlimited bookmarkResponse
+	s.expect(http.MethodPost, "/bookmarks/create", token, map[string]any{"title": "Go", "url": "https://go.dev", "max_visits": 1}, http.StatusCreated, &limited)
+	s.expect(http.MethodPost, "/bookmarks/create", token, map[string]any{"title": "Ent", "url": "https://entgo.io"}, http.StatusCreated, &unlimited)
+
+	s.client.Bookmark.Use(func(next ent.Mutator) ent.Mutator {
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			if _, ok := m.(*ent.BookmarkMutation).AddedVisitCount(); ok {
+				return nil, errors.New("database is down")
+			}
+			return next.Mutate(ctx, m)
+		})
+	})
+
+	var body errorResponse
+	s.expect(http.MethodGet, "/"+limited.ShortCode, "", nil, http.StatusServiceUnavailable, &body)
+	if body.Error != "Could not record the visit, try again" {
+		t.Fatalf("error = %q", body.Error)
+	}
+	// Links without a limit are counted later, so they still redirect
+	s.expect(http.MethodGet, "/"+unlimited.ShortCode, "", nil, http.StatusFound, nil)
+}
+
+func TestExpiredLink(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+	b := s.createBookmark(token, "Go", "https://go.dev")
+
+	var errResp errorResponse
+	past := time.Now().Add(-time.Hour)
+	s.expect(http.MethodPatch, "/bookmarks/"+b.ID, token, map[string]any{"expires_at": past}, http.StatusBadRequest, &errResp)
+	if errResp.Error != "expires_at must be in the future" {
+		t.Fatalf("error = %q", errResp.Error)
+	}
+
+	// Links can only be given a future expiry, so let this one run out in
+	// the database
+	err := s.client.Bookmark.UpdateOneID(uuid.MustParse(b.ID)).SetExpiresAt(past).Exec(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.expect(http.MethodGet, "/"+b.ShortCode, "", nil, http.StatusGone, nil)
+}
+
+func TestClearLimits(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+
+	var b bookmarkResponse
+	s.expect(http.MethodPost, "/bookmarks/create", token, map[string]any{"title": "Go", "url": "https://go.dev", "max_visits": 1}, http.StatusCreated, &b)
+	s.expect(http.MethodGet, "/"+b.ShortCode, "", nil, http.StatusFound, nil)
+	s.expect(http.MethodGet, "/"+b.ShortCode, "", nil, http.StatusGone, nil)
+
+	// Leaving a field out keeps it, null removes it
+	var limits struct {
+		ExpiresAt *time.Time `json:"expires_at"`
+		MaxVisits *int       `json:"max_visits"`
+	}
+	s.expect(http.MethodPatch, "/bookmarks/"+b.ID, token, map[string]any{"expires_at": time.Now().Add(time.Hour)}, http.StatusOK, &limits)
+	if limits.ExpiresAt == nil || limits.MaxVisits == nil {
+		t.Fatalf("unexpected limits %+v", limits)
+	}
+	limits.ExpiresAt, limits.MaxVisits = nil, nil
+	s.expect(http.MethodPatch, "/bookmarks/"+b.ID, token, map[string]any{"expires_at": nil, "max_visits": nil}, http.StatusOK, &limits)
+	if limits.ExpiresAt != nil || limits.MaxVisits != nil {
+		t.Fatalf("limits not cleared: %+v", limits)
+	}
+	s.expect(http.MethodGet, "/"+b.ShortCode, "", nil, http.StatusFound, nil)
+
+	s.expect(http.MethodPatch, "/bookmarks/"+b.ID, token, map[string]any{"max_visits": 0}, http.StatusBadRequest, nil)
+}
+
+func TestShortURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies string
+		headers        map[string]string
+		want           string
+	}{
+		{"base URL", "", nil, "https://sho.rt/"},
+		{"spoofed host", "", map[string]string{"Host": "evil.example", "X-Forwarded-Host": "evil.example"}, "https://sho.rt/"},
+		{"untrusted proxy", "10.0.0.0/8", map[string]string{"X-Forwarded-Proto": "http", "X-Forwarded-Host": "evil.example"}, "https://sho.rt/"},
+		{"trusted proxy", "127.0.0.1", map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "links.example"}, "https://links.example/"},
+		{"trusted proxy forwarded", "127.0.0.0/8", map[string]string{"Forwarded": `for=192.0.2.1;proto=https;host="links.example"`}, "https://links.example/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BASE_URL", "https://sho.rt")
+			t.Setenv("TRUSTED_PROXIES", tt.trustedProxies)
+			s := newTestServer(t)
+			token := s.login("alice@example.com")
+
+			body := strings.NewReader(`{"title":"Go","url":"https://go.dev"}`)
+			req, err := http.NewRequest(http.MethodPost, s.url+"/bookmarks/create", body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			req.Host = req.Header.Get("Host")
+
+			resp, err := s.http.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var b bookmarkResponse
+			if err := json.NewDecoder(resp.Body).Decode(&b); err != nil {
+				t.Fatal(err)
+			}
+			if b.ShortURL != tt.want+b.ShortCode {
+				t.Fatalf("short_url = %q, want %q", b.ShortURL, tt.want+b.ShortCode)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Setenv("RATE_LIMIT_AUTH", "3/1m")
+	s := newTestServer(t)
+	s.login("alice@example.com")
+
+	creds := map[string]string{"email": "alice@example.com", "password": "wrong-password"}
+	resp := s.expect(http.MethodPost, "/auth/token", "", creds, http.StatusUnauthorized, nil)
+	if got := resp.Header.Get("RateLimit-Remaining"); got != "0" {
+		t.Fatalf("RateLimit-Remaining = %q, want 0", got)
+	}
+
+	var body errorResponse
+	resp = s.expect(http.MethodPost, "/auth/token", "", creds, http.StatusTooManyRequests, &body)
+	// A token refills every 20s; slow password hashing (e.g. under -race)
+	// eats into the wait
+	wait, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || wait < 1 || wait > 20 || body.Error != "Too many requests" {
+		t.Fatalf("Retry-After = %q, error = %q", resp.Header.Get("Retry-After"), body.Error)
+	}
+
+	// The auth limit does not apply to other routes
+	s.expect(http.MethodGet, "/doesnotexist", "", nil, http.StatusNotFound, nil)
+}
+
+func TestMetrics(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+	b := s.createBookmark(token, "Go", "https://go.dev")
+
+	s.expect(http.MethodGet, "/"+b.ShortCode, "", nil, http.StatusFound, nil)
+	s.expect(http.MethodGet, "/"+b.ShortCode, "", nil, http.StatusFound, nil)
+	s.expect(http.MethodGet, "/doesnotexist", "", nil, http.StatusNotFound, nil)
+
+	resp, err := http.Get(s.url + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+
+	// Metrics are process-wide, so only check that the series exist
+	for _, series := range []string{
+		`http_requests_total{method="GET",route="/:code",status="302"}`,
+		`http_requests_total{method="GET",route="/:code",status="404"}`,
+		`http_request_duration_seconds_bucket{method="POST",route="/bookmarks/create",le="+Inf"}`,
+		`db_query_duration_seconds_count{operation="query"}`,
+		`redirects_total{result="found"}`,
+		`redirects_total{result="not_found"}`,
+		`shortcode_cache_lookups_total{result="hit"} 1`,
+		`shortcode_cache_lookups_total{result="miss"} 2`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(string(body), series) {
+			t.Errorf("metrics do not contain %s", series)
+		}
+	}
+	if strings.Contains(string(body), "/doesnotexist") || strings.Contains(string(body), b.ShortCode) {
+		t.Error("metrics contain a raw request path")
+	}
+}
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	s := newTestServer(t)
+	token := s.login("alice@example.com")
+	b := s.createBookmark(token, "Go", "https://go.dev")
+	exporter.Reset()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req, err := http.NewRequest(http.MethodGet, s.url+"/bookmarks/get/"+b.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	resp, err := s.http.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	var server sdktrace.ReadOnlySpan
+	var queries int
+	for _, span := range exporter.GetSpans().Snapshots() {
+		if span.SpanContext().TraceID().String() != traceID {
+			t.Errorf("span %q is in trace %s", span.Name(), span.SpanContext().TraceID())
+		}
+		switch span.SpanKind() {
+		case trace.SpanKindServer:
+			server = span
+		case trace.SpanKindClient:
+			if span.Name() == "SELECT" {
+				queries++
+			}
+		}
+	}
+	if server == nil || server.Name() != "GET /bookmarks/get/:id" {
+		t.Fatalf("missing server span, got %v", exporter.GetSpans().Snapshots())
+	}
+	if server.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("server span parent = %s", server.Parent().SpanID())
+	}
+	if queries == 0 {
+		t.Error("no database spans recorded")
+	}
+
+	// Error responses carry the trace ID
+	var errResp struct {
+		Error   string `json:"error"`
+		TraceID string `json:"trace_id"`
+	}
+	s.expect(http.MethodGet, "/doesnotexist", "", nil, http.StatusNotFound, &errResp)
+	if errResp.Error == "" || len(errResp.TraceID) != 32 {
+		t.Fatalf("unexpected error response %+v", errResp)
+	}
+}
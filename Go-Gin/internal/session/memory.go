@@ -0,0 +1,101 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"bookmark-shortener/internal/utils"
+)
+
+// sweepInterval bounds how often the memory store looks for expired
+// sessions.
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// MemoryStore is a process-local Store. Sessions do not survive restarts
+// and are not shared between instances, so it is meant for development and
+// tests.
+type MemoryStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	now       func() time.Time
+	sessions  map[string]memoryEntry
+	lastSweep time.Time
+}
+
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		ttl:      ttl,
+		now:      time.Now,
+		sessions: make(map[string]memoryEntry),
+	}
+}
+
+func (s *MemoryStore) Create(ctx context.Context, userID string) (string, error) {
+	id, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+	s.sessions[id] = memoryEntry{userID: userID, expiresAt: now.Add(s.ttl)}
+	return id, nil
+}
+
+func (s *MemoryStore) Validate(ctx context.Context, sessionID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	entry, ok := s.sessions[sessionID]
+	if !ok || !now.Before(entry.expiresAt) {
+		delete(s.sessions, sessionID)
+		return "", ErrNotFound
+	}
+
+	entry.expiresAt = now.Add(s.ttl)
+	s.sessions[sessionID] = entry
+	return entry.userID, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, sessionID)
+	return nil
+}
+
+func (s *MemoryStore) DeleteUser(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, entry := range s.sessions {
+		if entry.userID == userID {
+			delete(s.sessions, id)
+		}
+	}
+	return nil
+}
+
+// sweep drops expired sessions so abandoned ones don't accumulate.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for id, entry := range s.sessions {
+		if !now.Before(entry.expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"bookmark-shortener/internal/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisKeyPrefix = "session:"
+	// Each user's session IDs are kept in a set so they can all be ended
+	// at once. Members may outlive their sessions; the set expires with
+	// the user's last session.
+	redisUserKeyPrefix = "session:user:"
+)
+
+// RedisStore keeps sessions in any server speaking the Redis protocol
+// (Redis, Valkey, KeyDB, ...), so sessions are shared between instances.
+type RedisStore struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+func NewRedisStore(redisURL string, ttl time.Duration) (*RedisStore, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisStore{rdb: redis.NewClient(opts), ttl: ttl}, nil
+}
+
+func (s *RedisStore) Create(ctx context.Context, userID string) (string, error) {
+	id, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	userKey := redisUserKeyPrefix + userID
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, redisKeyPrefix+id, userID, s.ttl)
+		pipe.SAdd(ctx, userKey, id)
+		pipe.Expire(ctx, userKey, s.ttl)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *RedisStore) Validate(ctx context.Context, sessionID string) (string, error) {
+	// GETEX reads the session and pushes its expiry forward in one round trip
+	userID, err := s.rdb.GetEx(ctx, redisKeyPrefix+sessionID, s.ttl).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	// Keep the user's set alive as long as the session
+	if err := s.rdb.Expire(ctx, redisUserKeyPrefix+userID, s.ttl).Err(); err != nil {
+		return "", err
+	}
+	return userID, nil
+}
+
+func (s *RedisStore) Delete(ctx context.Context, sessionID string) error {
+	return s.rdb.Del(ctx, redisKeyPrefix+sessionID).Err()
+}
+
+func (s *RedisStore) DeleteUser(ctx context.Context, userID string) error {
+	userKey := redisUserKeyPrefix + userID
+	ids, err := s.rdb.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+	keys := []string{userKey}
+	for _, id := range ids {
+		keys = append(keys, redisKeyPrefix+id)
+	}
+	return s.rdb.Del(ctx, keys...).Err()
+}
+
+func (s *RedisStore) Close() error {
+	return s.rdb.Close()
+}
@@ -0,0 +1,191 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+const testTTL = time.Hour
+
+// fakeClock is advanced by hand.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newMemoryStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore(testTTL)
+	store.now = clock.Now
+	return store, clock
+}
+
+func newRedisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	store, err := NewRedisStore("redis://"+mr.Addr(), testTTL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store, mr
+}
+
+// testStore checks the behaviour every Store shares.
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	alice1, err := store.Create(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice2, err := store.Create(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := store.Create(ctx, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice1 == alice2 {
+		t.Fatal("Create returned the same session ID twice")
+	}
+
+	for id, want := range map[string]string{alice1: "alice", alice2: "alice", bob: "bob"} {
+		if got, err := store.Validate(ctx, id); err != nil || got != want {
+			t.Fatalf("Validate = %q, %v, want %q", got, err, want)
+		}
+	}
+	if _, err := store.Validate(ctx, "unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Validate(unknown) = %v, want ErrNotFound", err)
+	}
+
+	if err := store.Delete(ctx, alice1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Validate(ctx, alice1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Validate after Delete = %v, want ErrNotFound", err)
+	}
+	if _, err := store.Validate(ctx, alice2); err != nil {
+		t.Fatalf("Delete ended another session: %v", err)
+	}
+	// Deleting twice is not an error
+	if err := store.Delete(ctx, alice1); err != nil {
+		t.Fatal(err)
+	}
+
+	alice3, err := store.Create(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteUser(ctx, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{alice2, alice3} {
+		if _, err := store.Validate(ctx, id); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Validate after DeleteUser = %v, want ErrNotFound", err)
+		}
+	}
+	if got, err := store.Validate(ctx, bob); err != nil || got != "bob" {
+		t.Fatalf("DeleteUser ended another user's session: %q, %v", got, err)
+	}
+	if err := store.DeleteUser(ctx, "nobody"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store, _ := newMemoryStore()
+	testStore(t, store)
+}
+
+func TestRedisStore(t *testing.T) {
+	store, _ := newRedisStore(t)
+	testStore(t, store)
+}
+
+func TestMemoryStoreSlidingExpiry(t *testing.T) {
+	store, clock := newMemoryStore()
+	ctx := context.Background()
+
+	id, err := store.Create(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each use pushes the expiry forward, so the session outlives its TTL
+	for range 3 {
+		clock.Advance(testTTL - time.Second)
+		if _, err := store.Validate(ctx, id); err != nil {
+			t.Fatalf("Validate = %v while in use", err)
+		}
+	}
+
+	clock.Advance(testTTL)
+	if _, err := store.Validate(ctx, id); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Validate = %v after TTL of inactivity, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	store, clock := newMemoryStore()
+	ctx := context.Background()
+	create := func(userID string) {
+		t.Helper()
+		if _, err := store.Create(ctx, userID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	create("alice")
+	clock.Advance(testTTL - 10*time.Second)
+	create("bob")
+
+	// alice has expired, but the last sweep was moments ago
+	clock.Advance(20 * time.Second)
+	create("carol")
+	if len(store.sessions) != 3 {
+		t.Fatalf("%d sessions, want no sweep within sweepInterval", len(store.sessions))
+	}
+
+	clock.Advance(sweepInterval)
+	create("dave")
+	if len(store.sessions) != 3 {
+		t.Fatalf("%d sessions, want alice's swept", len(store.sessions))
+	}
+}
+
+func TestRedisStoreSlidingExpiry(t *testing.T) {
+	store, mr := newRedisStore(t)
+	ctx := context.Background()
+
+	id, err := store.Create(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		mr.FastForward(testTTL - time.Second)
+		if _, err := store.Validate(ctx, id); err != nil {
+			t.Fatalf("Validate = %v while in use", err)
+		}
+	}
+	// The user's set lives as long as the session, so DeleteUser still
+	// finds it
+	if ttl := mr.TTL(redisUserKeyPrefix + "alice"); ttl != testTTL {
+		t.Fatalf("user set TTL = %v, want %v", ttl, testTTL)
+	}
+
+	mr.FastForward(testTTL)
+	if _, err := store.Validate(ctx, id); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Validate = %v after TTL of inactivity, want ErrNotFound", err)
+	}
+	if mr.Exists(redisUserKeyPrefix + "alice") {
+		t.Fatal("user set outlived the user's last session")
+	}
+}
@@ -0,0 +1,24 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a session does not exist or has expired.
+var ErrNotFound = errors.New("session not found")
+
+// DefaultTTL matches the idle timeout used by the Express backend.
+const DefaultTTL = 24 * time.Hour
+
+// Store keeps opaque session IDs mapped to user IDs. Validate slides the
+// expiry forward, so a session only expires after TTL of inactivity.
+type Store interface {
+	Create(ctx context.Context, userID string) (string, error)
+	Validate(ctx context.Context, sessionID string) (string, error)
+	Delete(ctx context.Context, sessionID string) error
+	// DeleteUser ends every session of a user, such as after a password
+	// reset.
+	DeleteUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,136 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"entgo.io/ent/dialect"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Driver wraps an Ent driver to record a client span for every statement,
+// as a child of the span in the statement's context. Queries are recorded
+// with their placeholders, never with argument values.
+func Driver(drv dialect.Driver, dialectName string) dialect.Driver {
+	system := semconv.DBSystemPostgreSQL
+	if dialectName == dialect.SQLite {
+		system = semconv.DBSystemSqlite
+	}
+	return &tracedDriver{Driver: drv, system: system}
+}
+
+type tracedDriver struct {
+	dialect.Driver
+	system attribute.KeyValue
+}
+
+func (d *tracedDriver) Exec(ctx context.Context, query string, args, v any) error {
+	ctx, span := d.start(ctx, query)
+	return end(span, d.Driver.Exec(ctx, query, args, v))
+}
+
+func (d *tracedDriver) Query(ctx context.Context, query string, args, v any) error {
+	ctx, span := d.start(ctx, query)
+	return end(span, d.Driver.Query(ctx, query, args, v))
+}
+
+func (d *tracedDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	return d.BeginTx(ctx, nil)
+}
+
+func (d *tracedDriver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	spanCtx, span := d.start(ctx, "BEGIN")
+
+	var tx dialect.Tx
+	var err error
+	if b, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	}); ok {
+		tx, err = b.BeginTx(spanCtx, opts)
+	} else {
+		tx, err = d.Driver.Tx(spanCtx)
+	}
+	if end(span, err) != nil {
+		return nil, err
+	}
+	return &tracedTx{Tx: tx, driver: d, ctx: ctx}, nil
+}
+
+// ExecContext and QueryContext back the raw SQL methods of the Ent client.
+func (d *tracedDriver) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	drv, ok := d.Driver.(interface {
+		ExecContext(context.Context, string, ...any) (sql.Result, error)
+	})
+	if !ok {
+		return nil, errors.New("tracing: driver does not support ExecContext")
+	}
+	ctx, span := d.start(ctx, query)
+	res, err := drv.ExecContext(ctx, query, args...)
+	return res, end(span, err)
+}
+
+func (d *tracedDriver) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	drv, ok := d.Driver.(interface {
+		QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+	})
+	if !ok {
+		return nil, errors.New("tracing: driver does not support QueryContext")
+	}
+	ctx, span := d.start(ctx, query)
+	rows, err := drv.QueryContext(ctx, query, args...)
+	return rows, end(span, err)
+}
+
+func (d *tracedDriver) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := query
+	if i := strings.IndexAny(query, " \n\t"); i > 0 {
+		operation = query[:i]
+	}
+	operation = strings.ToUpper(operation)
+	return tracer().Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(d.system, semconv.DBOperationName(operation), semconv.DBQueryText(query)),
+	)
+}
+
+func end(span trace.Span, err error) error {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	return err
+}
+
+// tracedTx keeps the context the transaction was started with, so commit
+// and rollback, which take none, still belong to the request's trace.
+type tracedTx struct {
+	dialect.Tx
+	driver *tracedDriver
+	ctx    context.Context
+}
+
+func (t *tracedTx) Exec(ctx context.Context, query string, args, v any) error {
+	ctx, span := t.driver.start(ctx, query)
+	return end(span, t.Tx.Exec(ctx, query, args, v))
+}
+
+func (t *tracedTx) Query(ctx context.Context, query string, args, v any) error {
+	ctx, span := t.driver.start(ctx, query)
+	return end(span, t.Tx.Query(ctx, query, args, v))
+}
+
+func (t *tracedTx) Commit() error {
+	_, span := t.driver.start(t.ctx, "COMMIT")
+	return end(span, t.Tx.Commit())
+}
+
+func (t *tracedTx) Rollback() error {
+	_, span := t.driver.start(t.ctx, "ROLLBACK")
+	return end(span, t.Tx.Rollback())
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const emailVerificationPurpose = "email-verification"
+
+// EmailClaims prove control of Email for the user in Subject.
+type EmailClaims struct {
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// GenerateEmailToken signs a verification token for userID's email valid
+// for ttl. It stops working if the account's email changes.
+func GenerateEmailToken(userID, email string, ttl time.Duration, secret []byte) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, EmailClaims{
+		Email: email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{emailVerificationPurpose},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+	return token.SignedString(purposeKey(secret, emailVerificationPurpose))
+}
+
+func ValidateEmailToken(tokenString string, secret []byte) (*EmailClaims, error) {
+	claims := &EmailClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return purposeKey(secret, emailVerificationPurpose), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(emailVerificationPurpose),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// purposeKey derives a signing key per kind of token from secret, so a
+// token of one kind can never pass for another, such as an access token.
+func purposeKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
@@ -4,29 +4,43 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+const AccessTokenTTL = 15 * time.Minute
+
 type Claims struct {
-	UserID string `json:"user_id"`
+	UserID   string `json:"user_id"`
+	FamilyID string `json:"fid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID string, secret []byte) (string, error) {
+// GenerateToken signs an access token for userID. The returned jti lets the
+// token be revoked together with the refresh token family it belongs to.
+func GenerateToken(userID, familyID string, secret []byte) (string, string, error) {
+	jti := uuid.NewString()
+	now := time.Now()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
-		UserID: userID,
+		UserID:   userID,
+		FamilyID: familyID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	})
 
-	return token.SignedString(secret)
+	tokenString, err := token.SignedString(secret)
+	if err != nil {
+		return "", "", err
+	}
+	return tokenString, jti, nil
 }
 
 func ValidateToken(tokenString string, secret []byte) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return secret, nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil || !token.Valid {
 		return nil, err
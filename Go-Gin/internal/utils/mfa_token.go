@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const mfaChallengePurpose = "mfa-challenge"
+
+// GenerateMFAToken signs a challenge for userID, who passed the password
+// check, to finish logging in with a second factor within ttl.
+func GenerateMFAToken(userID string, ttl time.Duration, secret []byte) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   userID,
+		Audience:  jwt.ClaimStrings{mfaChallengePurpose},
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+	})
+	return token.SignedString(purposeKey(secret, mfaChallengePurpose))
+}
+
+// ValidateMFAToken returns the user ID of a challenge from GenerateMFAToken.
+func ValidateMFAToken(tokenString string, secret []byte) (string, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return purposeKey(secret, mfaChallengePurpose), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(mfaChallengePurpose),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
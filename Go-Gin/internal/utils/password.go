@@ -1,6 +1,10 @@
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 func HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -13,3 +17,23 @@ func HashPassword(password string) (string, error) {
 func CheckPassword(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
+
+// dummyHash is compared against when there is no account, at the same cost
+// as real password hashes.
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// PrecomputeDummyHash generates the hash CheckPasswordWithoutUser compares
+// against. Call it at startup so no login pays for generating it.
+func PrecomputeDummyHash() {
+	dummyHash()
+}
+
+// CheckPasswordWithoutUser takes as long as CheckPassword and always fails,
+// so logins for unknown emails can't be told apart by their response time.
+func CheckPasswordWithoutUser(password string) error {
+	bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+	return bcrypt.ErrMismatchedHashAndPassword
+}
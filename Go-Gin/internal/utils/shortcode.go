@@ -2,11 +2,39 @@ package utils
 
 import (
 	"crypto/rand"
+	"errors"
 	"math/big"
+	"strings"
 )
 
 const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
+const (
+	MinAliasLength = 3
+	MaxAliasLength = 32
+)
+
+var (
+	ErrInvalidAlias  = errors.New("alias must be 3-32 characters of letters, digits, '-' or '_'")
+	ErrReservedAlias = errors.New("alias is reserved")
+)
+
+// reservedAliases are path segments served by the router itself. A short
+// code with one of these names would be shadowed by, or shadow, a route.
+var reservedAliases = map[string]struct{}{
+	"admin":     {},
+	"api":       {},
+	"auth":      {},
+	"bookmarks": {},
+	"healthz":   {},
+	"login":     {},
+	"logout":    {},
+	"metrics":   {},
+	"readyz":    {},
+	"register":  {},
+	"static":    {},
+}
+
 func GenerateShortCode() string {
 	length := 6
 	result := make([]byte, length)
@@ -18,3 +46,20 @@ func GenerateShortCode() string {
 
 	return string(result)
 }
+
+// ValidateAlias checks a user-chosen short code against the charset, length
+// and reserved word policy. Uniqueness is left to the caller.
+func ValidateAlias(alias string) error {
+	if len(alias) < MinAliasLength || len(alias) > MaxAliasLength {
+		return ErrInvalidAlias
+	}
+	for _, r := range alias {
+		if !strings.ContainsRune(charset, r) && r != '-' && r != '_' {
+			return ErrInvalidAlias
+		}
+	}
+	if _, ok := reservedAliases[strings.ToLower(alias)]; ok {
+		return ErrReservedAlias
+	}
+	return nil
+}
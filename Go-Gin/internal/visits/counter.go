@@ -0,0 +1,197 @@
+package visits
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"bookmark-shortener/internal/analytics"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultFlushInterval = time.Second
+	DefaultBatchSize     = 1000
+
+	// maxPendingClicks bounds memory if the database stays unavailable;
+	// beyond it new click events are dropped (visit counts never are).
+	maxPendingClicks = 100000
+)
+
+// Sink persists aggregated visits. AddVisits must apply the deltas
+// atomically in the database (UPDATE ... SET visit_count = visit_count + n)
+// so that concurrent writers from other instances are not overwritten.
+type Sink interface {
+	AddVisits(ctx context.Context, counts map[uuid.UUID]int) error
+	RecordClicks(ctx context.Context, clicks []analytics.Click) error
+}
+
+// Counter takes visit increments and click events off the redirect path.
+// They are aggregated in memory and written in batches every flush interval
+// or once batch size events are pending, whichever comes first. Failed
+// writes are retried on the next flush.
+type Counter struct {
+	sink      Sink
+	interval  time.Duration
+	batchSize int
+
+	mu      sync.Mutex
+	counts  map[uuid.UUID]int
+	clicks  []analytics.Click
+	pending int
+
+	// flushMu serialises flushes so retries keep their order
+	flushMu sync.Mutex
+
+	kick      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewCounter(sink Sink, interval time.Duration, batchSize int) *Counter {
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	c := &Counter{
+		sink:      sink,
+		interval:  interval,
+		batchSize: batchSize,
+		counts:    make(map[uuid.UUID]int),
+		kick:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// Add counts one visit of a bookmark.
+func (c *Counter) Add(bookmarkID uuid.UUID) {
+	c.mu.Lock()
+	c.counts[bookmarkID]++
+	c.pending++
+	full := c.pending >= c.batchSize
+	c.mu.Unlock()
+
+	if full {
+		c.trigger()
+	}
+}
+
+// AddClick queues a click event.
+func (c *Counter) AddClick(click analytics.Click) {
+	c.mu.Lock()
+	if len(c.clicks) >= maxPendingClicks {
+		c.mu.Unlock()
+		return
+	}
+	c.clicks = append(c.clicks, click)
+	c.pending++
+	full := c.pending >= c.batchSize
+	c.mu.Unlock()
+
+	if full {
+		c.trigger()
+	}
+}
+
+// Flush writes everything buffered so far.
+func (c *Counter) Flush(ctx context.Context) error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	counts, clicks := c.counts, c.clicks
+	c.counts = make(map[uuid.UUID]int)
+	c.clicks = nil
+	c.pending = 0
+	c.mu.Unlock()
+
+	var firstErr error
+	if len(counts) > 0 {
+		if err := c.sink.AddVisits(ctx, counts); err != nil {
+			firstErr = err
+			c.requeueCounts(counts)
+		}
+	}
+	if len(clicks) > 0 {
+		if err := c.sink.RecordClicks(ctx, clicks); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			c.requeueClicks(clicks)
+		}
+	}
+	return firstErr
+}
+
+// Close stops the background flusher and drains the buffer. It must be
+// called after the last Add, typically once the HTTP server has shut down.
+func (c *Counter) Close(ctx context.Context) error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+	})
+	<-c.done
+	return c.Flush(ctx)
+}
+
+func (c *Counter) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		case <-c.kick:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*c.interval)
+		if err := c.Flush(ctx); err != nil {
+			slog.Error("Failed to flush visit counts", "error", err)
+		}
+		cancel()
+	}
+}
+
+func (c *Counter) trigger() {
+	select {
+	case c.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Counter) requeueCounts(counts map[uuid.UUID]int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, n := range counts {
+		c.counts[id] += n
+		c.pending += n
+	}
+}
+
+func (c *Counter) requeueClicks(clicks []analytics.Click) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	room := maxPendingClicks - len(c.clicks)
+	if room <= 0 {
+		return
+	}
+	if len(clicks) > room {
+		clicks = clicks[len(clicks)-room:]
+	}
+	c.clicks = append(clicks, c.clicks...)
+	c.pending += len(clicks)
+}
@@ -0,0 +1,144 @@
+package visits
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"bookmark-shortener/internal/analytics"
+
+	"github.com/google/uuid"
+)
+
+// memorySink sums flushed counts and can be told to fail.
+type memorySink struct {
+	mu      sync.Mutex
+	counts  map[uuid.UUID]int
+	clicks  int
+	flushes int
+	fail    bool
+}
+
+func newMemorySink() *memorySink {
+	return &memorySink{counts: make(map[uuid.UUID]int)}
+}
+
+func (s *memorySink) AddVisits(ctx context.Context, counts map[uuid.UUID]int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fail {
+		return errors.New("database unavailable")
+	}
+	s.flushes++
+	for id, n := range counts {
+		s.counts[id] += n
+	}
+	return nil
+}
+
+func (s *memorySink) RecordClicks(ctx context.Context, clicks []analytics.Click) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fail {
+		return errors.New("database unavailable")
+	}
+	s.clicks += len(clicks)
+	return nil
+}
+
+func TestCounterConcurrentAddsLoseNothing(t *testing.T) {
+	sink := newMemorySink()
+	// A tiny batch size forces many flushes to race with the adds
+	counter := NewCounter(sink, time.Millisecond, 7)
+
+	ids := make([]uuid.UUID, 10)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	const workers, perWorker = 50, 2000
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				id := ids[(w+i)%len(ids)]
+				counter.Add(id)
+				counter.AddClick(analytics.Click{BookmarkID: id})
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if err := counter.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	total := 0
+	for _, id := range ids {
+		total += sink.counts[id]
+	}
+	if total != workers*perWorker {
+		t.Fatalf("flushed %d visits, want %d", total, workers*perWorker)
+	}
+	for _, id := range ids {
+		if want := workers * perWorker / len(ids); sink.counts[id] != want {
+			t.Errorf("bookmark %s: flushed %d visits, want %d", id, sink.counts[id], want)
+		}
+	}
+	if sink.clicks != workers*perWorker {
+		t.Errorf("flushed %d clicks, want %d", sink.clicks, workers*perWorker)
+	}
+	if sink.flushes < 2 {
+		t.Errorf("expected batched flushes, got %d", sink.flushes)
+	}
+}
+
+func TestCounterRetriesFailedFlush(t *testing.T) {
+	sink := newMemorySink()
+	counter := NewCounter(sink, time.Hour, 1000)
+	id := uuid.New()
+
+	sink.fail = true
+	counter.Add(id)
+	counter.Add(id)
+	if err := counter.Flush(context.Background()); err == nil {
+		t.Fatal("expected flush to fail")
+	}
+
+	sink.fail = false
+	counter.Add(id)
+	if err := counter.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if sink.counts[id] != 3 {
+		t.Fatalf("flushed %d visits, want 3", sink.counts[id])
+	}
+}
+
+func TestCounterFlushesOnInterval(t *testing.T) {
+	sink := newMemorySink()
+	counter := NewCounter(sink, 10*time.Millisecond, 1000)
+	defer counter.Close(context.Background())
+
+	id := uuid.New()
+	counter.Add(id)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		sink.mu.Lock()
+		n := sink.counts[id]
+		sink.mu.Unlock()
+		if n == 1 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("visit was not flushed by the interval timer")
+}
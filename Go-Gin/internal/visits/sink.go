@@ -0,0 +1,78 @@
+package visits
+
+import (
+	"context"
+	"fmt"
+
+	"bookmark-shortener/ent"
+	"bookmark-shortener/ent/bookmark"
+	"bookmark-shortener/internal/analytics"
+
+	"github.com/google/uuid"
+)
+
+// EntSink writes visit counts and click events through Ent.
+type EntSink struct {
+	client   *ent.Client
+	recorder *analytics.Recorder
+}
+
+func NewEntSink(client *ent.Client, recorder *analytics.Recorder) *EntSink {
+	return &EntSink{
+		client:   client,
+		recorder: recorder,
+	}
+}
+
+func (s *EntSink) AddVisits(ctx context.Context, counts map[uuid.UUID]int) error {
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return err
+	}
+
+	for id, n := range counts {
+		// Bookmarks deleted since the visit match no rows, which is fine
+		err := tx.Bookmark.Update().
+			Where(bookmark.ID(id)).
+			AddVisitCount(n).
+			Exec(ctx)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("bookmark %s: %w", id, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *EntSink) RecordClicks(ctx context.Context, clicks []analytics.Click) error {
+	// Drop clicks on bookmarks deleted since the redirect, otherwise the
+	// foreign key would fail the whole batch on every retry
+	seen := make(map[uuid.UUID]bool)
+	var ids []uuid.UUID
+	for _, click := range clicks {
+		if !seen[click.BookmarkID] {
+			seen[click.BookmarkID] = true
+			ids = append(ids, click.BookmarkID)
+		}
+	}
+	existing, err := s.client.Bookmark.Query().Where(bookmark.IDIn(ids...)).IDs(ctx)
+	if err != nil {
+		return err
+	}
+	live := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		live[id] = true
+	}
+
+	kept := clicks[:0:0]
+	for _, click := range clicks {
+		if live[click.BookmarkID] {
+			kept = append(kept, click)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return s.recorder.Record(ctx, kept)
+}
@@ -1,63 +1,146 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"bookmark-shortener/internal/config"
-	"bookmark-shortener/internal/handlers"
-	"bookmark-shortener/internal/middleware"
+	"bookmark-shortener/internal/logging"
+	"bookmark-shortener/internal/metrics"
+	"bookmark-shortener/internal/server"
+	"bookmark-shortener/internal/tracing"
 
-	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
 
 func main() {
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "optional YAML or TOML config file, overridden by environment variables")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration with secrets redacted and exit")
+	flag.Parse()
+
 	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
-	}
+	envErr := godotenv.Load()
 
 	// Initialize configuration
-	cfg := config.New()
+	if *printConfig {
+		cfg, err := config.Read(*configFile)
+		if err != nil {
+			fatal("Invalid configuration", err)
+		}
+		cfg.Print(os.Stdout)
+		if err := cfg.Validate(); err != nil {
+			fatal("Invalid configuration", err)
+		}
+		return
+	}
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		fatal("Invalid configuration", err)
+	}
+
+	// Initialize logging; the standard log package writes through it too
+	logger, err := logging.New(os.Stderr, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		fatal("Invalid logging configuration", err)
+	}
+	slog.SetDefault(logger)
+	if envErr != nil {
+		slog.Info("No .env file found")
+	}
+	slog.Debug("Configuration loaded", "config", cfg)
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TraceExporter)
+	if err != nil {
+		fatal("Failed to initialize tracing", err)
+	}
 
 	// Initialize database and Ent client
 	client, err := cfg.InitDB()
 	if err != nil {
-		log.Fatal("Failed to initialize database:", err)
+		fatal("Failed to initialize database", err)
 	}
 	defer client.Close()
 
-	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(client, cfg.JWTSecret)
-	bookmarkHandler := handlers.NewBookmarkHandler(client)
-	redirectHandler := handlers.NewRedirectHandler(client)
+	// Setup routes
+	router, err := server.NewRouter(cfg, client)
+	if err != nil {
+		fatal("Failed to initialize router", err)
+	}
 
-	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTSecret)
+	srv := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      router,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
 
-	// Setup routes
-	r := gin.Default()
+	// Metrics on a separate port stay reachable for scrapers only
+	var admin *http.Server
+	if cfg.MetricsPort != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		admin = &http.Server{
+			Addr:        ":" + cfg.MetricsPort,
+			Handler:     mux,
+			ReadTimeout: cfg.ReadTimeout,
+		}
+		go func() {
+			slog.Info("Metrics available", "port", cfg.MetricsPort)
+			if err := admin.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fatal("Metrics server failed", err)
+			}
+		}()
+	}
 
-	// Auth routes
-	auth := r.Group("/auth")
-	{
-		auth.POST("/register", authHandler.Register)
-		auth.POST("/token", authHandler.Login)
+	go func() {
+		slog.Info("Server starting", "port", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("Server failed", err)
+		}
+	}()
+
+	// Wait for a termination signal
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	stop()
+
+	// Fail readiness first and give the load balancer time to notice before
+	// we stop accepting connections
+	slog.Info("Shutting down, draining connections")
+	router.Drain()
+	time.Sleep(cfg.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Server did not drain in time", "error", err)
+	}
+	if admin != nil {
+		admin.Shutdown(shutdownCtx)
 	}
 
-	// Protected bookmark routes
-	bookmarks := r.Group("/bookmarks")
-	bookmarks.Use(authMiddleware.RequireAuth())
-	{
-		bookmarks.POST("/create", bookmarkHandler.Create)
-		bookmarks.GET("/get", bookmarkHandler.GetAll)
-		bookmarks.GET("/get/:id", bookmarkHandler.GetByID)
-		bookmarks.DELETE("/delete/:id", bookmarkHandler.Delete)
+	// No more requests can add visits now, flush what is buffered
+	if err := router.Close(shutdownCtx); err != nil {
+		slog.Error("Failed to flush visit counts", "error", err)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
 	}
 
-	// Short URL redirect
-	r.GET("/:code", redirectHandler.Redirect)
+	slog.Info("Server stopped")
+}
 
-	log.Printf("Server starting on port %s", cfg.Port)
-	r.Run(":" + cfg.Port)
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }